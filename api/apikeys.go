@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+// CreateApiKeyRequest creates a key, zero rate limit fields fall back to the configured defaults
+type CreateApiKeyRequest struct {
+	Name                string `json:"name"`
+	RateLimitMax        int    `json:"rate_limit_max"`
+	RateLimitExpiration int    `json:"rate_limit_expiration"`
+	DailyQuota          int64  `json:"daily_quota"`
+}
+
+// CreateApiKeyResponse carries the secret key, it is not retrievable later
+type CreateApiKeyResponse struct {
+	*store.ApiKey
+	Key string `json:"key"`
+}
+
+func registerListApiKeys(app *fiber.App, engine *core.Engine) {
+	app.Get("/keys", requireRole(constants.RoleOperator), func(c *fiber.Ctx) error {
+		keys, err := engine.ListApiKeys()
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+		return c.JSON(keys)
+	})
+}
+
+func registerCreateApiKey(app *fiber.App, engine *core.Engine) {
+	app.Post("/keys", requireRole(constants.RoleAdmin), audited(engine, "create_api_key"), func(c *fiber.Ctx) error {
+		var request CreateApiKeyRequest
+		if err := json.Unmarshal(c.Body(), &request); err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+		request.Name = strings.TrimSpace(request.Name)
+		if request.Name == "" {
+			return respondError(c, fiber.StatusBadRequest, "name is required")
+		}
+		if request.RateLimitMax < 0 || request.RateLimitExpiration < 0 || request.DailyQuota < 0 {
+			return respondError(c, fiber.StatusBadRequest, "limits can not be negative")
+		}
+
+		apiKey, key, err := engine.CreateApiKey(request.Name, request.RateLimitMax, request.RateLimitExpiration, request.DailyQuota)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+		return c.Status(fiber.StatusCreated).JSON(CreateApiKeyResponse{ApiKey: apiKey, Key: key})
+	})
+}
+
+func registerRevokeApiKey(app *fiber.App, engine *core.Engine) {
+	app.Post("/keys/:id/revoke", requireRole(constants.RoleAdmin), audited(engine, "revoke_api_key"), func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		if err := engine.RevokeApiKey(id); err != nil {
+			if errors.Is(err, constants.ErrNotFound) {
+				return respondError(c, fiber.StatusNotFound, "Api key not found or already revoked")
+			}
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+const principalLocal = "principal"
+
+// principal is the authenticated client of a private api request
+type principal struct {
+	Name string
+	Role constants.PrivateApiRole
+}
+
+func authenticateToken(c *fiber.Ctx, clients []configuration.PrivateApiClient) *principal {
+	token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok || token == "" {
+		return nil
+	}
+	var result *principal
+	// compare with every client to not leak which tokens exist through timing
+	for _, client := range clients {
+		if client.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(client.Token)) == 1 {
+			result = &principal{Name: client.Name, Role: client.Role}
+		}
+	}
+	return result
+}
+
+// authenticateCertificate matches the common name of a verified client certificate with the client names
+func authenticateCertificate(c *fiber.Ctx, clients []configuration.PrivateApiClient) *principal {
+	state := c.Context().TLSConnectionState()
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	commonName := state.PeerCertificates[0].Subject.CommonName
+	for _, client := range clients {
+		if client.Name == commonName {
+			return &principal{Name: client.Name, Role: client.Role}
+		}
+	}
+	return nil
+}
+
+// newAuthenticator identifies the client by bearer token or client certificate. Without configured
+// clients every request is rejected.
+func newAuthenticator(config *configuration.PrivateApiConfiguration) fiber.Handler {
+	clients := config.Clients
+	return func(c *fiber.Ctx) error {
+		p := authenticateToken(c, clients)
+		if p == nil {
+			p = authenticateCertificate(c, clients)
+		}
+		if p == nil {
+			slog.Warn("rejected unauthenticated private api request", "path", c.Path(), "remote_address", c.IP())
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return respondError(c, fiber.StatusUnauthorized, "authentication required")
+		}
+		c.Locals(principalLocal, p)
+		return c.Next()
+	}
+}
+
+func getPrincipal(c *fiber.Ctx) *principal {
+	if p, ok := c.Locals(principalLocal).(*principal); ok {
+		return p
+	}
+	return nil
+}
+
+// requireRole rejects clients without the role, admins have every role
+func requireRole(role constants.PrivateApiRole) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		p := getPrincipal(c)
+		if p == nil || (p.Role != role && p.Role != constants.RoleAdmin) {
+			return respondError(c, fiber.StatusForbidden, "role "+string(role)+" required")
+		}
+		return c.Next()
+	}
+}
+
+// audited records the action with the client which triggered it once the handler returned
+func audited(engine *core.Engine, action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		entry := &store.AuditEntry{
+			Time:          time.Now().UTC(),
+			Action:        action,
+			Request:       c.Method() + " " + c.OriginalURL(),
+			RemoteAddress: c.IP(),
+			Status:        c.Response().StatusCode(),
+		}
+		if p := getPrincipal(c); p != nil {
+			entry.Actor, entry.Role = p.Name, string(p.Role)
+		}
+		slog.Info("private api action", "actor", entry.Actor, "role", entry.Role, "action", action, "request", entry.Request, "status", entry.Status)
+		if auditErr := engine.RecordAuditEntry(entry); auditErr != nil {
+			slog.Error("failed to record audit entry", "action", action, "actor", entry.Actor, "error", auditErr.Error())
+		}
+		return err
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateApiAuthorization(t *testing.T) {
+	config := &configuration.Runtime{}
+	config.PrivateApi.Clients = []configuration.PrivateApiClient{
+		{Name: "monitoring", Role: constants.RoleOperator, Token: "operator-token"},
+		{Name: "deploy", Role: constants.RoleAdmin, Token: "admin-token"},
+	}
+	app := newPrivateApp(config, nil)
+
+	// requests which pass authorization are rejected by the handler before the engine is used
+	for name, tc := range map[string]struct {
+		method string
+		target string
+		token  string
+		status int
+	}{
+		"spec is public":             {fiber.MethodGet, "/openapi.json", "", fiber.StatusOK},
+		"missing token":              {fiber.MethodGet, "/explain/abc/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL", "", fiber.StatusUnauthorized},
+		"unknown token":              {fiber.MethodGet, "/explain/abc/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL", "other", fiber.StatusUnauthorized},
+		"operator reads":             {fiber.MethodGet, "/explain/abc/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL", "operator-token", fiber.StatusBadRequest},
+		"operator can not fetch":     {fiber.MethodPost, "/fetch/cycle/abc", "operator-token", fiber.StatusForbidden},
+		"operator can not prune":     {fiber.MethodPost, "/prune", "operator-token", fiber.StatusForbidden},
+		"operator can not read logs": {fiber.MethodGet, "/audit", "operator-token", fiber.StatusForbidden},
+		"admin reads":                {fiber.MethodGet, "/explain/abc/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL", "admin-token", fiber.StatusBadRequest},
+		"admin reads logs":           {fiber.MethodGet, "/audit?limit=0", "admin-token", fiber.StatusBadRequest},
+	} {
+		req := httptest.NewRequest(tc.method, tc.target, nil)
+		if tc.token != "" {
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+tc.token)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err, name)
+		assert.Equal(t, tc.status, resp.StatusCode, name)
+	}
+}
+
+func TestPrivateApiWithoutClients(t *testing.T) {
+	config := &configuration.Runtime{PrivateListen: "127.0.0.1:0"}
+	_, err := CreatePrivateApi(config, nil)
+	assert.Error(t, err)
+
+	resp, err := newPrivateApp(config, nil).Test(httptest.NewRequest(fiber.MethodGet, "/explain/abc/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
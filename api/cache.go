@@ -0,0 +1,198 @@
+package api
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/signing"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+const cacheStateLocal = "cache_state"
+
+// cachedHeaders are replayed with cached responses
+var cachedHeaders = []string{
+	fiber.HeaderContentType,
+	fiber.HeaderETag,
+	fiber.HeaderCacheControl,
+	signing.HeaderSignature,
+	signing.HeaderSigner,
+	signing.HeaderPublicKey,
+	signing.HeaderSubject,
+}
+
+// cachedResponse is a delegation state response kept until the stored state changes
+type cachedResponse struct {
+	key      string
+	cycle    int64
+	delegate string
+	body     []byte
+	headers  map[string]string
+}
+
+// responseCache is a least recently used cache of responses invalidated by store changes
+type responseCache struct {
+	mtx     sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+	// incremented on every invalidation, responses built before are not added
+	generation uint64
+}
+
+func newResponseCache(size int) *responseCache {
+	return &responseCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (rc *responseCache) get(key string) (*cachedResponse, bool) {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	element, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	rc.order.MoveToFront(element)
+	return element.Value.(*cachedResponse), true
+}
+
+func (rc *responseCache) getGeneration() uint64 {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	return rc.generation
+}
+
+// add keeps the response unless the cache was invalidated since generation
+func (rc *responseCache) add(response *cachedResponse, generation uint64) {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	if generation != rc.generation {
+		return
+	}
+	if element, ok := rc.entries[response.key]; ok {
+		element.Value = response
+		rc.order.MoveToFront(element)
+		return
+	}
+	rc.entries[response.key] = rc.order.PushFront(response)
+	for rc.order.Len() > rc.size {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+// invalidate drops responses of the delegate in the stored cycle, of every delegate if delegate is nil
+func (rc *responseCache) invalidate(cycle int64, delegate *mavryk.Address) {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	rc.generation++
+	for element := rc.order.Front(); element != nil; {
+		next := element.Next()
+		response := element.Value.(*cachedResponse)
+		if response.cycle == cycle && (delegate == nil || response.delegate == delegate.String()) {
+			rc.order.Remove(element)
+			delete(rc.entries, response.key)
+		}
+		element = next
+	}
+}
+
+// setStateCacheHeaders marks the response as a representation of the stored state. Stored states can be
+// replaced by a forced re-fetch so caches have to revalidate them, only archived states are immutable.
+func setStateCacheHeaders(c *fiber.Ctx, state *store.StoredDelegationState) {
+	if state.Archived {
+		c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d, immutable", constants.CACHE_IMMUTABLE_MAX_AGE_SECONDS))
+	} else {
+		c.Set(fiber.HeaderCacheControl, "public, no-cache")
+	}
+	c.Locals(cacheStateLocal, state)
+}
+
+// computeETag identifies the revision of the state in the representation of the request, the signer
+// is included as it changes the signature headers and envelopes
+func computeETag(c *fiber.Ctx, state *store.StoredDelegationState) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%d\n%d\n%d\n%s", c.OriginalURL(), state.Delegate.String(), state.Cycle,
+		state.Revision, state.StoredAt.UnixNano(), c.GetRespHeader(signing.HeaderPublicKey))))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func matchesETag(c *fiber.Ctx, etag string) bool {
+	for _, candidate := range strings.Split(c.Get(fiber.HeaderIfNoneMatch), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func notModified(c *fiber.Ctx) error {
+	c.Status(fiber.StatusNotModified)
+	c.Response().ResetBody()
+	return nil
+}
+
+// cacheable adds an ETag derived from the state revision to delegation state responses and answers a matching
+// If-None-Match with 304. With a cache responses are served from memory until the state changes.
+func cacheable(cache *responseCache) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// fiber strings point into the request buffers that are reused after the handler returns
+		key := strings.Clone(c.OriginalURL())
+		var generation uint64
+		if cache != nil {
+			if response, ok := cache.get(key); ok {
+				for header, value := range response.headers {
+					c.Set(header, value)
+				}
+				if matchesETag(c, response.headers[fiber.HeaderETag]) {
+					return notModified(c)
+				}
+				return c.Send(response.body)
+			}
+			generation = cache.getGeneration()
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		state, ok := c.Locals(cacheStateLocal).(*store.StoredDelegationState)
+		if !ok || c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		body := c.Response().Body()
+		etag := computeETag(c, state)
+		c.Set(fiber.HeaderETag, etag)
+		if cache != nil {
+			response := &cachedResponse{
+				key:      key,
+				cycle:    state.Cycle,
+				delegate: state.Delegate.String(),
+				body:     bytes.Clone(body),
+				headers:  make(map[string]string, len(cachedHeaders)),
+			}
+			for _, header := range cachedHeaders {
+				if value := c.GetRespHeader(header); value != "" {
+					response.headers[header] = strings.Clone(value)
+				}
+			}
+			cache.add(response, generation)
+		}
+		if matchesETag(c, etag) {
+			return notModified(c)
+		}
+		return nil
+	}
+}
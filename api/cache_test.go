@@ -0,0 +1,96 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCacheEviction(t *testing.T) {
+	cache := newResponseCache(2)
+	for _, key := range []string{"a", "b"} {
+		cache.add(&cachedResponse{key: key, cycle: 1}, cache.getGeneration())
+	}
+	_, _ = cache.get("a")
+	cache.add(&cachedResponse{key: "c", cycle: 2}, cache.getGeneration())
+
+	_, ok := cache.get("b")
+	assert.False(t, ok, "least recently used entry is evicted")
+	_, ok = cache.get("a")
+	assert.True(t, ok)
+
+	generation := cache.getGeneration()
+	cache.invalidate(1, nil)
+	_, ok = cache.get("a")
+	assert.False(t, ok)
+	_, ok = cache.get("c")
+	assert.True(t, ok)
+
+	cache.add(&cachedResponse{key: "d", cycle: 1}, generation)
+	_, ok = cache.get("d")
+	assert.False(t, ok, "responses built before an invalidation are not added")
+}
+
+func TestCacheable(t *testing.T) {
+	delegate, err := mavryk.ParseAddress("mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL")
+	require.NoError(t, err)
+
+	calls := 0
+	revision := int64(1)
+	cache := newResponseCache(10)
+	app := fiber.New()
+	app.Get("/state", cacheable(cache), func(c *fiber.Ctx) error {
+		calls++
+		state := &store.StoredDelegationState{Delegate: store.Address{Address: delegate}, Cycle: 10, Revision: revision}
+		setStateCacheHeaders(c, state)
+		return c.JSON(state)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/state", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, no-cache", resp.Header.Get(fiber.HeaderCacheControl), "stored states are revalidated")
+	etag := resp.Header.Get(fiber.HeaderETag)
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/state", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+	assert.Equal(t, 1, calls, "served from the cache")
+
+	cache.invalidate(10, &delegate)
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/state", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, etag, resp.Header.Get(fiber.HeaderETag))
+	assert.Equal(t, 2, calls)
+
+	revision = 2
+	cache.invalidate(10, &delegate)
+	req = httptest.NewRequest(fiber.MethodGet, "/state", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode, "a new revision changes the etag")
+	assert.NotEqual(t, etag, resp.Header.Get(fiber.HeaderETag))
+}
+
+func TestArchivedStateIsImmutable(t *testing.T) {
+	app := fiber.New()
+	app.Get("/state", cacheable(nil), func(c *fiber.Ctx) error {
+		state := &store.StoredDelegationState{Cycle: 10, Revision: 1, Archived: true}
+		setStateCacheHeaders(c, state)
+		return c.JSON(state)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/state", nil))
+	require.NoError(t, err)
+	assert.Contains(t, resp.Header.Get(fiber.HeaderCacheControl), "immutable")
+}
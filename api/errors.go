@@ -0,0 +1,14 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrorResponse is the body of every non successful response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func respondError(c *fiber.Ctx, status int, message string) error {
+	return c.Status(status).JSON(ErrorResponse{Error: message})
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/core"
+)
+
+const eventFilterLocal = "event_filter"
+
+func parseEventFilter(c *fiber.Ctx) (core.EventFilter, error) {
+	delegates, err := parseAddresses(c.Query("delegates"))
+	return core.EventFilter(delegates), err
+}
+
+// registerEvents streams engine events as server-sent events
+func registerEvents(app *fiber.App, engine *core.Engine) {
+	app.Get("/events", func(c *fiber.Ctx) error {
+		filter, err := parseEventFilter(c)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+
+		events, cancel := engine.SubscribeEvents()
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer cancel()
+			keepalive := time.NewTicker(constants.EVENTS_KEEPALIVE_INTERVAL_SECONDS * time.Second)
+			defer keepalive.Stop()
+
+			for {
+				select {
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					if !filter.Match(&event) {
+						continue
+					}
+					data, err := json.Marshal(&event)
+					if err != nil {
+						slog.Error("failed to encode event", "error", err.Error())
+						continue
+					}
+					fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Kind, data)
+				case <-keepalive.C:
+					fmt.Fprint(w, ": keepalive\n\n")
+				}
+				// flush fails once the client is gone
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		})
+		return nil
+	})
+}
+
+// registerEventsWebSocket streams engine events as json text messages
+func registerEventsWebSocket(app *fiber.App, engine *core.Engine) {
+	app.Use("/events/ws", func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return respondError(c, fiber.StatusUpgradeRequired, "websocket upgrade required")
+		}
+		filter, err := parseEventFilter(c)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+		c.Locals(eventFilterLocal, filter)
+		return c.Next()
+	})
+
+	app.Get("/events/ws", websocket.New(func(conn *websocket.Conn) {
+		filter, _ := conn.Locals(eventFilterLocal).(core.EventFilter)
+		events, cancel := engine.SubscribeEvents()
+		defer cancel()
+
+		// the client does not send anything, reading only detects when it goes away
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		keepalive := time.NewTicker(constants.EVENTS_KEEPALIVE_INTERVAL_SECONDS * time.Second)
+		defer keepalive.Stop()
+		for {
+			var err error
+			select {
+			case <-closed:
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if !filter.Match(&event) {
+					continue
+				}
+				err = conn.WriteJSON(&event)
+			case <-keepalive.C:
+				err = conn.WriteMessage(websocket.PingMessage, nil)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}))
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/protocol-rewards/core"
+)
+
+// registerHealth serves liveness and readiness probes, they are registered before the rate limiter
+func registerHealth(app *fiber.App, engine *core.Engine) {
+	app.Get("/healthz", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"status": "ok"})
+	})
+
+	app.Get("/readyz", func(c *fiber.Ctx) error {
+		readiness := engine.CheckReadiness(c.Context())
+		if !readiness.Ready {
+			c.Status(fiber.StatusServiceUnavailable)
+		}
+		return c.JSON(readiness)
+	})
+}
+
+func registerStatus(app *fiber.App, engine *core.Engine) {
+	app.Get("/status", func(c *fiber.Ctx) error {
+		return c.JSON(engine.GetStatus(c.Context()))
+	})
+}
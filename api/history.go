@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+var historyCsvHeader = []string{"cycle", "own_delegated", "own_staked", "external_delegated", "external_staked", "external_overstaked", "delegators", "baking_power", "status"}
+
+func writeHistoryCsv(w io.Writer, records []store.DelegateCycleRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(historyCsvHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		err := writer.Write([]string{
+			strconv.FormatInt(r.Cycle, 10),
+			strconv.FormatInt(r.OwnDelegated, 10),
+			strconv.FormatInt(r.OwnStaked, 10),
+			strconv.FormatInt(r.ExternalDelegated, 10),
+			strconv.FormatInt(r.ExternalStaked, 10),
+			strconv.FormatInt(r.ExternalOverstaked, 10),
+			strconv.FormatInt(r.Delegators, 10),
+			strconv.FormatInt(r.BakingPower, 10),
+			r.Status,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func parseHistoryRange(c *fiber.Ctx) (int64, int64, error) {
+	from, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid from cycle")
+	}
+	to, err := strconv.ParseInt(c.Query("to"), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid to cycle")
+	}
+	if to < from || to-from >= constants.HISTORY_MAX_CYCLES {
+		return 0, 0, fmt.Errorf("to has to be at least from and the range at most %d cycles", constants.HISTORY_MAX_CYCLES)
+	}
+	return from, to, nil
+}
+
+func registerGetDelegateHistory(app *fiber.App, engine *core.Engine) {
+	app.Get("/delegate/:address/history", func(c *fiber.Ctx) error {
+		address, err := mavryk.ParseAddress(c.Params("address"))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		from, to, err := parseHistoryRange(c)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		format := c.Query("format", "json")
+		if format != "json" && format != "csv" {
+			return respondError(c, fiber.StatusBadRequest, "format has to be json or csv")
+		}
+
+		records, err := engine.GetDelegateHistory(c.Context(), address, from, to)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		if format == "csv" {
+			c.Set(fiber.HeaderContentType, "text/csv")
+			c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"history-%s-%d-%d.csv\"", address.String(), from, to))
+			return writeHistoryCsv(c, records)
+		}
+		return c.JSON(records)
+	})
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegateHistoryValidation(t *testing.T) {
+	app := newTestPublicApp()
+	for name, target := range map[string]string{
+		"missing to":    "/delegate/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL/history?from=10",
+		"reverse range": "/delegate/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL/history?from=12&to=10",
+		"long range":    "/delegate/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL/history?from=10&to=5000",
+		"format":        "/delegate/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL/history?from=10&to=12&format=xml",
+	} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, name)
+
+		// served by the history handler, not taken as /delegate/:cycle/:address
+		var body ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.NotContains(t, body.Error, "parsing", name)
+	}
+}
+
+func TestWriteHistoryCsv(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeHistoryCsv(&buf, []store.DelegateCycleRecord{
+		{Cycle: 750, OwnDelegated: 1, OwnStaked: 2, ExternalDelegated: 3, ExternalStaked: 4, ExternalOverstaked: 1, Delegators: 5, BakingPower: 8, Status: "ok"},
+		{Cycle: 751, Delegators: 1},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "cycle,own_delegated,own_staked,external_delegated,external_staked,external_overstaked,delegators,baking_power,status\n"+
+		"750,1,2,3,4,1,5,8,ok\n"+
+		"751,0,0,0,0,0,1,0,\n", buf.String())
+}
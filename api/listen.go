@@ -0,0 +1,61 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/protocol-rewards/configuration"
+)
+
+// serve binds the address before serving in the background, so startup fails if the address is not available
+func serve(app *fiber.App, name, address string, tlsConfig *tls.Config) error {
+	listener, err := net.Listen(app.Config().Network, address)
+	if err != nil {
+		return fmt.Errorf("failed to start %s: %w", name, err)
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	go func() {
+		if err := app.Listener(listener); err != nil {
+			slog.Error(name+" stopped", "error", err.Error())
+		}
+	}()
+	return nil
+}
+
+// privateTlsConfig returns nil without a certificate, client certificates are required with a client ca
+func privateTlsConfig(config *configuration.PrivateApiConfiguration) (*tls.Config, error) {
+	if config.TlsCertificate == "" {
+		return nil, nil
+	}
+	certificate, err := tls.LoadX509KeyPair(config.TlsCertificate, config.TlsKey)
+	if err != nil {
+		return nil, err
+	}
+	result := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if config.TlsClientCa == "" {
+		return result, nil
+	}
+
+	data, err := os.ReadFile(config.TlsClientCa)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", config.TlsClientCa)
+	}
+	result.ClientCAs = pool
+	result.ClientAuth = tls.RequireAndVerifyClientCert
+	return result, nil
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	_ "embed"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	//go:embed openapi/public.json
+	publicOpenApi []byte
+	//go:embed openapi/private.json
+	privateOpenApi []byte
+)
+
+func registerOpenApi(app *fiber.App, spec []byte) {
+	app.Get("/openapi.json", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Send(spec)
+	})
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/protocol-rewards/client"
+	"github.com/mavryk-network/protocol-rewards/common"
+	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/merkle"
+	"github.com/mavryk-network/protocol-rewards/signing"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type openApiDocument struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*openApiSchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type openApiSchema struct {
+	Ref                  string                    `json:"$ref"`
+	Properties           map[string]*openApiSchema `json:"properties"`
+	Items                *openApiSchema            `json:"items"`
+	AdditionalProperties *openApiSchema            `json:"additionalProperties"`
+}
+
+var routeParam = regexp.MustCompile(`:(\w+)`)
+
+// documentedRoutes returns method and path of every operation in the spec
+func documentedRoutes(t *testing.T, spec []byte) map[string]bool {
+	var document openApiDocument
+	require.NoError(t, json.Unmarshal(spec, &document))
+	result := make(map[string]bool)
+	for path, operations := range document.Paths {
+		for method := range operations {
+			result[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+	return result
+}
+
+// registeredRoutes returns method and path of every handler of the app in the openapi path syntax
+func registeredRoutes(app *fiber.App) map[string]bool {
+	result := make(map[string]bool)
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		result[route.Method+" "+routeParam.ReplaceAllString(route.Path, "{$1}")] = true
+	}
+	return result
+}
+
+func newTestPublicApp() *fiber.App {
+	config := &configuration.Runtime{}
+	config.RateLimit.Max = 1000
+	config.RateLimit.Expiration = 1
+	app, err := newPublicApp(config, nil)
+	if err != nil {
+		panic(err)
+	}
+	return app
+}
+
+func TestOpenApiMatchesRoutes(t *testing.T) {
+	for name, tc := range map[string]struct {
+		app  *fiber.App
+		spec []byte
+	}{
+		"public":  {newTestPublicApp(), publicOpenApi},
+		"private": {newPrivateApp(&configuration.Runtime{}, nil), privateOpenApi},
+	} {
+		documented := documentedRoutes(t, tc.spec)
+		registered := registeredRoutes(tc.app)
+		for route := range registered {
+			assert.True(t, documented[route], "%s: %s is not documented", name, route)
+		}
+		for route := range documented {
+			assert.True(t, registered[route], "%s: %s is documented but not served", name, route)
+		}
+	}
+}
+
+// jsonFields returns the json field types of the struct including fields of embedded structs
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	result := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			for key, value := range jsonFields(embedded) {
+				result[key] = value
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		result[name] = field.Type
+	}
+	return result
+}
+
+// elementType unwraps pointers, slices and maps down to the type described by an inline schema
+func elementType(typ reflect.Type) reflect.Type {
+	for {
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			typ = typ.Elem()
+		default:
+			return typ
+		}
+	}
+}
+
+// compareSchema checks the properties of the schema against the json fields of typ, inline object
+// schemas of properties are compared against the field types
+func compareSchema(t *testing.T, path string, schema *openApiSchema, typ reflect.Type) {
+	fields := jsonFields(typ)
+	assert.Equal(t, sortedKeys(schema.Properties), sortedKeys(fields), "%s: schema properties differ from %s", path, typ)
+	for name, property := range schema.Properties {
+		field, ok := fields[name]
+		if !ok {
+			continue
+		}
+		for _, inline := range []*openApiSchema{property, property.Items, property.AdditionalProperties} {
+			if inline == nil || inline.Properties == nil {
+				continue
+			}
+			if element := elementType(field); element.Kind() == reflect.Struct {
+				compareSchema(t, path+"."+name, inline, element)
+			}
+		}
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestOpenApiSchemasMatchTypes(t *testing.T) {
+	for name, tc := range map[string]struct {
+		spec  []byte
+		types map[string][]any
+	}{
+		"public": {publicOpenApi, map[string][]any{
+			"ErrorResponse":                 {ErrorResponse{}, client.ErrorResponse{}},
+			"DelegatorBalances":             {common.DelegatorBalances{}, client.DelegatorBalances{}},
+			"StoredDelegationState":         {store.StoredDelegationState{}, client.StoredDelegationState{}},
+			"StakingParameters":             {common.StakingParameters{}, client.StakingParameters{}},
+			"OverstakeAnalytics":            {common.OverstakeAnalytics{}, client.OverstakeAnalytics{}},
+			"DelegationStateBalance":        {store.DelegationStateBalance{}, client.DelegationStateBalance{}},
+			"DelegationStateRevision":       {store.DelegationStateRevision{}, client.DelegationStateRevision{}},
+			"DelegatorBalanceChange":        {store.DelegatorBalanceChange{}, client.DelegatorBalanceChange{}},
+			"DelegationStateCycleDiff":      {store.DelegationStateCycleDiff{}, client.DelegationStateCycleDiff{}},
+			"DelegationStateRevisionDiff":   {core.DelegationStateRevisionDiff{}, client.DelegationStateRevisionDiff{}},
+			"CycleSummary":                  {store.CycleSummary{}, client.CycleSummary{}},
+			"MerkleLeaf":                    {merkle.Leaf{}},
+			"InclusionProof":                {merkle.InclusionProof{}},
+			"CycleStatistics":               {common.CycleStatistics{}, client.CycleStatistics{}},
+			"DelegateCycleRecord":           {store.DelegateCycleRecord{}, client.DelegateCycleRecord{}},
+			"DelegatorSizeStatistics":       {common.DelegatorSizeStatistics{}, client.DelegatorSizeStatistics{}},
+			"MvktLikeDelegationState":       {store.MvktLikeDelegationState{}, client.MvktLikeDelegationState{}},
+			"DelegationStatesQueryRequest":  {DelegationStatesQueryRequest{}, client.DelegationStatesQueryRequest{}},
+			"DelegationStatesQueryResponse": {DelegationStatesQueryResponse{}, client.DelegationStatesQueryResponse{}},
+			"Event":                         {core.Event{}},
+			"Envelope":                      {signing.Envelope{}},
+		}},
+		"private": {privateOpenApi, map[string][]any{
+			"ErrorResponse":              {ErrorResponse{}, client.ErrorResponse{}},
+			"ApiKey":                     {store.ApiKeyWithUsage{}, client.ApiKey{}},
+			"CreateApiKeyRequest":        {CreateApiKeyRequest{}, client.CreateApiKeyRequest{}},
+			"RetentionReport":            {core.RetentionReport{}, client.RetentionReport{}},
+			"AuditEntry":                 {store.AuditEntry{}, client.AuditEntry{}},
+			"FetchResponse":              {FetchResponse{}, client.FetchResponse{}},
+			"DelegationStateExplanation": {core.DelegationStateExplanation{}, client.DelegationStateExplanation{}},
+		}},
+	} {
+		var document openApiDocument
+		require.NoError(t, json.Unmarshal(tc.spec, &document))
+		for schemaName, types := range tc.types {
+			schema, ok := document.Components.Schemas[schemaName]
+			require.True(t, ok, "%s: schema %s is missing", name, schemaName)
+			for _, value := range types {
+				compareSchema(t, name+" "+schemaName, schema, reflect.TypeOf(value))
+			}
+		}
+	}
+}
+
+func TestOpenApiServed(t *testing.T) {
+	resp, err := newTestPublicApp().Test(httptest.NewRequest(fiber.MethodGet, "/openapi.json", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var document openApiDocument
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&document))
+	assert.Contains(t, document.Paths, "/delegate/{cycle}/{address}")
+}
+
+func TestErrorResponse(t *testing.T) {
+	resp, err := newTestPublicApp().Test(httptest.NewRequest(fiber.MethodGet, "/delegate/abc/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var body ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.NotEmpty(t, body.Error)
+}
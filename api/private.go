@@ -1,73 +1,237 @@
 package api
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/mavryk-network/mvgo/mavryk"
 	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/mavryk-network/protocol-rewards/constants"
 	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/export"
+	"github.com/mavryk-network/protocol-rewards/store"
 )
 
+// cancelingWriter cancels the export once the client is gone, fasthttp does not cancel the request context
+type cancelingWriter struct {
+	w      io.Writer
+	cancel context.CancelFunc
+}
+
+func (cw *cancelingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	if err != nil {
+		cw.cancel()
+	}
+	return n, err
+}
+
+func parseAddresses(value string) ([]mavryk.Address, error) {
+	result := make([]mavryk.Address, 0)
+	if value == "" {
+		return result, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		address, err := mavryk.ParseAddress(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, address)
+	}
+	return result, nil
+}
+
+// FetchResponse acknowledges a fetch which continues in the background
+type FetchResponse struct {
+	Cycle   int64  `json:"cycle"`
+	Address string `json:"address,omitempty"`
+}
+
 func registerFetchCycle(app *fiber.App, engine *core.Engine) {
-	app.Get("/fetch/cycle/:cycle", func(c *fiber.Ctx) error {
+	app.Post("/fetch/cycle/:cycle", requireRole(constants.RoleAdmin), audited(engine, "fetch_cycle"), func(c *fiber.Ctx) error {
 		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
 
 		go engine.FetchCycleDelegationStates(c.Context(), cycle, 0, &core.FetchOptions{
-			Force: c.Query("force") == "true",
-		})
-		return c.JSON(fiber.Map{
-			"cycle": cycle,
+			Force:  c.Query("force") == "true",
+			Reason: store.RevisionReasonApi,
 		})
+		return c.JSON(FetchResponse{Cycle: cycle})
 	})
 }
 
 func registerFetchDelegate(app *fiber.App, engine *core.Engine) {
-	app.Get("/fetch/delegate/:cycle/:address", func(c *fiber.Ctx) error {
+	app.Post("/fetch/delegate/:cycle/:address", requireRole(constants.RoleAdmin), audited(engine, "fetch_delegate"), func(c *fiber.Ctx) error {
 		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
 
 		address, err := mavryk.ParseAddress(c.Params("address"))
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
 
 		go engine.FetchDelegateDelegationState(c.Context(), address, cycle, 0, &core.FetchOptions{
-			Force: c.Query("force") == "true",
-		})
-		return c.JSON(fiber.Map{
-			"cycle":   cycle,
-			"address": address.String(),
+			Force:  c.Query("force") == "true",
+			Reason: store.RevisionReasonApi,
 		})
+		return c.JSON(FetchResponse{Cycle: cycle, Address: address.String()})
 	})
 }
 
-func CreatePrivateApi(config *configuration.Runtime, engine *core.Engine) *fiber.App {
-	if config.PrivateListen == "" {
+func registerExplainDelegationState(app *fiber.App, engine *core.Engine) {
+	app.Get("/explain/:cycle/:address", requireRole(constants.RoleOperator), func(c *fiber.Ctx) error {
+		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		address, err := mavryk.ParseAddress(c.Params("address"))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		explanation, err := engine.ExplainDelegationState(c.Context(), address, cycle, 0)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(explanation)
+	})
+}
+
+func registerExport(app *fiber.App, engine *core.Engine) {
+	app.Get("/export", requireRole(constants.RoleOperator), audited(engine, "export"), func(c *fiber.Ctx) error {
+		from, err := strconv.ParseInt(c.Query("from"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		to := from
+		if c.Query("to") != "" {
+			to, err = strconv.ParseInt(c.Query("to"), 10, 64)
+			if err != nil || to < from {
+				return respondError(c, fiber.StatusBadRequest, "invalid to cycle")
+			}
+		}
+
+		delegates, err := parseAddresses(c.Query("delegates"))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		format, err := export.ParseFormat(c.Query("format", string(export.FormatCSV)))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		filter := &export.Filter{FromCycle: from, ToCycle: to, Delegates: delegates}
+		c.Set(fiber.HeaderContentType, format.ContentType())
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"delegation-states-%d-%d.%s\"", from, to, format))
+		requestCtx := c.Context()
+		requestCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+			ctx, cancel := context.WithCancel(requestCtx)
+			defer cancel()
+			// headers are already sent, errors can only be logged
+			count, err := engine.ExportDelegationStates(ctx, filter, format, &cancelingWriter{w: w, cancel: cancel})
+			if err != nil {
+				slog.Error("failed to export delegation states", "from", from, "to", to, "error", err.Error())
+			}
+			if err := w.Flush(); err != nil {
+				slog.Warn("failed to flush export", "error", err.Error())
+			}
+			slog.Debug("exported delegation states", "from", from, "to", to, "rows", count)
+		})
 		return nil
-	}
-	app := fiber.New()
-	registerFetchCycle(app, engine)
-	registerFetchDelegate(app, engine)
+	})
+}
+
+func registerPrune(app *fiber.App, engine *core.Engine) {
+	app.Post("/prune", requireRole(constants.RoleAdmin), audited(engine, "prune"), func(c *fiber.Ctx) error {
+		var cycle int64
+		if c.Query("cycle") != "" {
+			var err error
+			if cycle, err = strconv.ParseInt(c.Query("cycle"), 10, 64); err != nil {
+				return respondError(c, fiber.StatusBadRequest, err.Error())
+			}
+		} else {
+			lastFetchedCycle, err := engine.GetLastFetchedCycle()
+			if err != nil {
+				return respondError(c, fiber.StatusInternalServerError, err.Error())
+			}
+			cycle = lastFetchedCycle
+		}
 
-	go func() {
-		err := app.Listen(config.PrivateListen)
+		report, err := engine.ApplyRetention(cycle)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+		return c.JSON(report)
+	})
+}
+
+func registerListAuditEntries(app *fiber.App, engine *core.Engine) {
+	app.Get("/audit", requireRole(constants.RoleAdmin), func(c *fiber.Ctx) error {
+		limit := c.QueryInt("limit", constants.AUDIT_DEFAULT_LIMIT)
+		if limit <= 0 || limit > constants.AUDIT_MAX_LIMIT {
+			return respondError(c, fiber.StatusBadRequest, fmt.Sprintf("limit has to be between 1 and %d", constants.AUDIT_MAX_LIMIT))
+		}
+		before, err := strconv.ParseInt(c.Query("before", "0"), 10, 64)
 		if err != nil {
-			slog.Error("failed to start public api", "error", err.Error())
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
-		// FIXME This is suboptimal, ideally we should have a way to wait for the server to start
-	}()
+
+		entries, err := engine.ListAuditEntries(limit, before)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+		return c.JSON(entries)
+	})
+}
+
+func newPrivateApp(config *configuration.Runtime, engine *core.Engine) *fiber.App {
+	app := fiber.New()
+	registerOpenApi(app, privateOpenApi)
+	app.Use(newAuthenticator(&config.PrivateApi))
+
+	registerFetchCycle(app, engine)
+	registerFetchDelegate(app, engine)
+	registerExplainDelegationState(app, engine)
+	registerExport(app, engine)
+	registerPrune(app, engine)
+	registerListApiKeys(app, engine)
+	registerCreateApiKey(app, engine)
+	registerRevokeApiKey(app, engine)
+	registerListAuditEntries(app, engine)
 	return app
 }
+
+// CreatePrivateApi serves the private api if a listen address is configured, nil is returned otherwise
+func CreatePrivateApi(config *configuration.Runtime, engine *core.Engine) (*fiber.App, error) {
+	if config.PrivateListen == "" {
+		return nil, nil
+	}
+	if len(config.PrivateApi.Clients) == 0 {
+		return nil, errors.New("private api requires at least one client")
+	}
+	tlsConfig, err := privateTlsConfig(&config.PrivateApi)
+	if err != nil {
+		return nil, err
+	}
+
+	app := newPrivateApp(config, engine)
+	if err := serve(app, "private api", config.PrivateListen, tlsConfig); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
@@ -2,48 +2,63 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"strconv"
-	"time"
+	"sync/atomic"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/mavryk-network/mvgo/mavryk"
 	"github.com/mavryk-network/protocol-rewards/configuration"
 	"github.com/mavryk-network/protocol-rewards/constants"
 	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/signing"
 	"github.com/mavryk-network/protocol-rewards/store"
 )
 
-func registerGetDelegationState(app *fiber.App, engine *core.Engine) {
-	app.Get("/delegate/:cycle/:address", func(c *fiber.Ctx) error {
+// respond writes v as json. With a signer the body is the canonical encoding of v
+// and the signature is attached in headers, or as an envelope if requested by ?envelope=true.
+func respond(c *fiber.Ctx, signer *signing.Signer, v any, subject *signing.Subject) error {
+	if signer == nil {
+		return c.JSON(v)
+	}
+	envelope, err := signer.Sign(v, subject)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, err.Error())
+	}
+	if c.QueryBool("envelope") {
+		return c.JSON(envelope)
+	}
+	c.Set(signing.HeaderSignature, envelope.Signature)
+	c.Set(signing.HeaderSigner, envelope.Signer)
+	c.Set(signing.HeaderPublicKey, envelope.PublicKey)
+	c.Set(signing.HeaderSubject, subject.String())
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(envelope.Payload)
+}
+
+func registerGetDelegationState(app *fiber.App, engine *core.Engine, signer *signing.Signer, cache *responseCache) {
+	app.Get("/delegate/:cycle/:address", cacheable(cache), func(c *fiber.Ctx) error {
 		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
 
 		address, err := mavryk.ParseAddress(c.Params("address"))
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
 
 		state, err := engine.GetDelegationState(c.Context(), address, cycle)
 		if err != nil {
 			if errors.Is(err, constants.ErrNotFound) {
-				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-					"error": "Delegation state not found",
-				})
+				return respondError(c, fiber.StatusNotFound, "Delegation state not found")
 			}
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
 		}
 
-		return c.JSON(state)
+		setStateCacheHeaders(c, state)
+		return respond(c, signer, state, signing.NewSubject(address, cycle))
 	})
 }
 
@@ -51,107 +66,386 @@ func registerIsDelegationStateAvailable(app *fiber.App, engine *core.Engine) {
 	app.Get("/delegate/:cycle/:address/available", func(c *fiber.Ctx) error {
 		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
 
 		address, err := mavryk.ParseAddress(c.Params("address"))
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
 
 		available, err := engine.IsDelegationStateAvailable(c.Context(), address, cycle)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
 		}
 
 		return c.JSON(available)
 	})
 }
 
+func registerGetDelegatorBalances(app *fiber.App, engine *core.Engine) {
+	app.Get("/delegator/:cycle/:address", func(c *fiber.Ctx) error {
+		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		address, err := mavryk.ParseAddress(c.Params("address"))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		balances, err := engine.GetDelegatorBalances(c.Context(), address, cycle)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(balances)
+	})
+}
+
+func registerGetTopDelegators(app *fiber.App, engine *core.Engine) {
+	app.Get("/delegate/:cycle/:address/top", func(c *fiber.Ctx) error {
+		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		address, err := mavryk.ParseAddress(c.Params("address"))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		limit := c.QueryInt("limit", constants.TOP_DELEGATORS_DEFAULT_LIMIT)
+		if limit <= 0 || limit > constants.TOP_DELEGATORS_MAX_LIMIT {
+			return respondError(c, fiber.StatusBadRequest, fmt.Sprintf("limit has to be between 1 and %d", constants.TOP_DELEGATORS_MAX_LIMIT))
+		}
+
+		delegators, err := engine.GetTopDelegators(c.Context(), address, cycle, limit)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(delegators)
+	})
+}
+
+func registerGetOverstakeAnalytics(app *fiber.App, engine *core.Engine) {
+	app.Get("/delegate/:cycle/:address/overstake", func(c *fiber.Ctx) error {
+		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		address, err := mavryk.ParseAddress(c.Params("address"))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		analytics, err := engine.GetOverstakeAnalytics(c.Context(), address, cycle)
+		if err != nil {
+			switch {
+			case errors.Is(err, constants.ErrNotFound):
+				return respondError(c, fiber.StatusNotFound, "Delegation state not found")
+			case errors.Is(err, constants.ErrStakingParametersNotAvailable):
+				return respondError(c, fiber.StatusNotFound, "Staking parameters were not stored with the delegation state")
+			}
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(analytics)
+	})
+}
+
+func registerGetDelegationStateRevisions(app *fiber.App, engine *core.Engine) {
+	app.Get("/delegate/:cycle/:address/revisions", func(c *fiber.Ctx) error {
+		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		address, err := mavryk.ParseAddress(c.Params("address"))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		revisions, err := engine.GetDelegationStateRevisions(c.Context(), address, cycle)
+		if err != nil {
+			if errors.Is(err, constants.ErrNotFound) {
+				return respondError(c, fiber.StatusNotFound, "Delegation state not found")
+			}
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(revisions)
+	})
+}
+
+func registerDiffDelegationStateRevisions(app *fiber.App, engine *core.Engine) {
+	app.Get("/delegate/:cycle/:address/revisions/diff", func(c *fiber.Ctx) error {
+		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		address, err := mavryk.ParseAddress(c.Params("address"))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		from, err := strconv.ParseInt(c.Query("from"), 10, 64)
+		if err != nil || from <= 0 {
+			return respondError(c, fiber.StatusBadRequest, "from has to be a revision number")
+		}
+		to := int64(c.QueryInt("to", 0))
+		if to < 0 {
+			return respondError(c, fiber.StatusBadRequest, "to has to be a revision number")
+		}
+
+		diff, err := engine.DiffDelegationStateRevisions(c.Context(), address, cycle, from, to)
+		if err != nil {
+			if errors.Is(err, constants.ErrNotFound) {
+				return respondError(c, fiber.StatusNotFound, "Revision not found")
+			}
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(diff)
+	})
+}
+
+func registerDiffDelegationStateCycles(app *fiber.App, engine *core.Engine) {
+	app.Get("/delegate/:address/diff/:fromCycle/:toCycle", func(c *fiber.Ctx) error {
+		address, err := mavryk.ParseAddress(c.Params("address"))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		fromCycle, err := strconv.ParseInt(c.Params("fromCycle"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+		toCycle, err := strconv.ParseInt(c.Params("toCycle"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+		if fromCycle >= toCycle {
+			return respondError(c, fiber.StatusBadRequest, "fromCycle has to be lower than toCycle")
+		}
+
+		diff, err := engine.DiffDelegationStateCycles(c.Context(), address, fromCycle, toCycle)
+		if err != nil {
+			if errors.Is(err, constants.ErrNotFound) {
+				return respondError(c, fiber.StatusNotFound, "Delegation state not found")
+			}
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(diff)
+	})
+}
+
+// parseVersion reads the optional commitment version, 0 means the latest version
+func parseVersion(c *fiber.Ctx) (int64, error) {
+	if c.Query("version") == "" {
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(c.Query("version"), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if version <= 0 {
+		return 0, errors.New("version must be positive")
+	}
+	return version, nil
+}
+
+func registerGetCycleRoot(app *fiber.App, engine *core.Engine) {
+	app.Get("/cycle/:cycle/root", func(c *fiber.Ctx) error {
+		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		version, err := parseVersion(c)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		summary, err := engine.GetCycleSummary(c.Context(), cycle, version)
+		if err != nil {
+			if errors.Is(err, constants.ErrNotFound) {
+				return respondError(c, fiber.StatusNotFound, "Cycle commitment not found")
+			}
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(summary)
+	})
+}
+
+func registerGetDelegatorProof(app *fiber.App, engine *core.Engine) {
+	app.Get("/delegator/:address/:cycle/proof", func(c *fiber.Ctx) error {
+		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		address, err := mavryk.ParseAddress(c.Params("address"))
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		version, err := parseVersion(c)
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		proofs, err := engine.GetDelegatorInclusionProofs(c.Context(), address, cycle, version)
+		switch {
+		case errors.Is(err, constants.ErrNotFound):
+			return respondError(c, fiber.StatusNotFound, err.Error())
+		case errors.Is(err, constants.ErrCommitmentOutdated):
+			return respondError(c, fiber.StatusConflict, err.Error())
+		case errors.Is(err, constants.ErrCyclePruned):
+			return respondError(c, fiber.StatusGone, err.Error())
+		case err != nil:
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		return c.JSON(proofs)
+	})
+}
+
 func registerStatistics(app *fiber.App, engine *core.Engine) {
 	app.Get("/statistics/:cycle", func(c *fiber.Ctx) error {
 		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
 
 		available, err := engine.Statisticts(c.Context(), cycle)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
 		}
 
 		return c.JSON(available)
 	})
 }
 
-func registerRewardsSplitMirror(app *fiber.App, engine *core.Engine) {
-	app.Get("/v1/rewards/split/:address/:cycle", func(c *fiber.Ctx) error {
+func registerRewardsSplitMirror(app *fiber.App, engine *core.Engine, signer *signing.Signer, cache *responseCache) {
+	app.Get("/v1/rewards/split/:address/:cycle", cacheable(cache), func(c *fiber.Ctx) error {
 		cycle, err := strconv.ParseInt(c.Params("cycle"), 10, 64)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
 
 		address, err := mavryk.ParseAddress(c.Params("address"))
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusBadRequest, err.Error())
 		}
 
 		state, err := engine.GetDelegationState(c.Context(), address, cycle)
 		if err != nil {
 			if errors.Is(err, constants.ErrNotFound) {
-				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-					"error": "Delegation state not found",
-				})
+				return respondError(c, fiber.StatusNotFound, "Delegation state not found")
 			}
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
 		}
 
 		switch state.Status {
 		case store.DelegationStateStatusMinimumNotAvailable:
-			return c.Status(fiber.StatusNoContent).JSON(fiber.Map{
-				"error": "relevant minimum does not exists",
-			})
+			// relevant minimum does not exist, no content can not carry a body
+			return c.SendStatus(fiber.StatusNoContent)
 		}
 
-		return c.JSON(state.ToMvktState())
+		setStateCacheHeaders(c, state)
+		// the payload carries neither the delegate nor the requested cycle, the subject binds them
+		return respond(c, signer, state.ToMvktState(), signing.NewSubject(address, cycle))
 	})
 }
 
-func CreatePublicApi(config *configuration.Runtime, engine *core.Engine) *fiber.App {
+var (
+	// limiter handler is rebuilt on configuration reload, requests always go through the latest one
+	publicLimiter atomic.Pointer[fiber.Handler]
+	// limits of the current limiter, it is only rebuilt when they change to keep the counters
+	publicLimits atomic.Pointer[configuration.RateLimitConfiguration]
+)
+
+func newLimiter(config *configuration.Runtime, engine *core.Engine) fiber.Handler {
+	return newRateLimiter(config, engine).handle
+}
+
+func storePublicLimiter(config *configuration.Runtime, engine *core.Engine) {
+	handler := newLimiter(config, engine)
+	limits := config.RateLimit
+	publicLimiter.Store(&handler)
+	publicLimits.Store(&limits)
+}
+
+// ReloadPublicApi applies reloadable configuration to the running public api
+func ReloadPublicApi(config *configuration.Runtime, engine *core.Engine) {
+	if current := publicLimits.Load(); current != nil && *current == config.RateLimit {
+		return
+	}
+	storePublicLimiter(config, engine)
+	slog.Info("public api rate limit reloaded", "max", config.RateLimit.Max, "expiration", config.RateLimit.Expiration, "shared", config.RateLimit.Shared)
+}
+
+// newPublicApp fails if a signing key is configured but can not be loaded
+func newPublicApp(config *configuration.Runtime, engine *core.Engine) (*fiber.App, error) {
+	var signer *signing.Signer
+	if config.SigningKey != "" {
+		var err error
+		if signer, err = signing.NewSigner(config.SigningKey); err != nil {
+			return nil, err
+		}
+		slog.Info("signing delegation state responses", "signer", signer.Address().String())
+	}
+
 	app := fiber.New()
+	registerOpenApi(app, publicOpenApi)
+	registerHealth(app, engine)
 
-	app.Use(limiter.New(limiter.Config{
-		Max:        10,
-		Expiration: 30 * time.Second,
-	}))
+	storePublicLimiter(config, engine)
+	app.Use(func(c *fiber.Ctx) error {
+		return (*publicLimiter.Load())(c)
+	})
 
-	registerGetDelegationState(app, engine)
+	var cache *responseCache
+	if config.ResponseCache.Size > 0 {
+		cache = newResponseCache(config.ResponseCache.Size)
+		engine.OnDelegationStatesChanged(cache.invalidate)
+	}
+
+	// before /delegate/:cycle/:address which would match it as well
+	registerGetDelegateHistory(app, engine)
+	registerGetDelegationState(app, engine, signer, cache)
 	registerIsDelegationStateAvailable(app, engine)
-	registerRewardsSplitMirror(app, engine)
+	registerRewardsSplitMirror(app, engine, signer, cache)
+	registerStatus(app, engine)
 	registerStatistics(app, engine)
+	registerGetDelegatorBalances(app, engine)
+	registerGetTopDelegators(app, engine)
+	registerGetOverstakeAnalytics(app, engine)
+	registerGetDelegationStateRevisions(app, engine)
+	registerDiffDelegationStateRevisions(app, engine)
+	registerDiffDelegationStateCycles(app, engine)
+	registerGetCycleRoot(app, engine)
+	registerGetDelegatorProof(app, engine)
+	registerQueryDelegationStates(app, engine)
+	registerEvents(app, engine)
+	registerEventsWebSocket(app, engine)
+	return app, nil
+}
 
-	go func() {
-		err := app.Listen(config.Listen)
-		if err != nil {
-			slog.Error("failed to start public api", "error", err.Error())
-		}
-		// FIXME This is suboptimal, ideally we should have a way to wait for the server to start
-	}()
-	return app
+func CreatePublicApi(config *configuration.Runtime, engine *core.Engine) (*fiber.App, error) {
+	app, err := newPublicApp(config, engine)
+	if err != nil {
+		return nil, err
+	}
+	if err := serve(app, "public api", config.Listen, nil); err != nil {
+		return nil, err
+	}
+	return app, nil
 }
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicAppRejectsInvalidSigningKey(t *testing.T) {
+	config := &configuration.Runtime{SigningKey: "edsk-invalid"}
+	_, err := newPublicApp(config, nil)
+	assert.ErrorIs(t, err, constants.ErrInvalidSigningKey)
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+type DelegationStatesQueryRequest struct {
+	Delegates []string `json:"delegates"`
+	FromCycle int64    `json:"from_cycle"`
+	ToCycle   int64    `json:"to_cycle"`
+	// fields of the states to return, all if empty. delegate, cycle and requested_cycle are always returned.
+	Fields []string `json:"fields,omitempty"`
+	Cursor string   `json:"cursor,omitempty"`
+	Limit  int      `json:"limit,omitempty"`
+}
+
+type DelegationStatesQueryResponse struct {
+	States     []map[string]json.RawMessage `json:"states"`
+	NextCursor string                       `json:"next_cursor,omitempty"`
+}
+
+var (
+	queryKeyFields      = []string{"delegate", "cycle", "requested_cycle"}
+	querySelectedFields = []string{"status", "balances", "revision", "reason", "stored_at", "staking_parameters"}
+)
+
+func (r *DelegationStatesQueryRequest) toStoreQuery() (store.DelegationStateQuery, error) {
+	query := store.DelegationStateQuery{
+		FromCycle: r.FromCycle,
+		ToCycle:   r.ToCycle,
+		Limit:     r.Limit,
+	}
+	if len(r.Delegates) == 0 || len(r.Delegates) > constants.QUERY_MAX_DELEGATES {
+		return query, fmt.Errorf("between 1 and %d delegates are required", constants.QUERY_MAX_DELEGATES)
+	}
+	for _, delegate := range r.Delegates {
+		address, err := mavryk.ParseAddress(delegate)
+		if err != nil {
+			return query, err
+		}
+		query.Delegates = append(query.Delegates, address)
+	}
+	if r.ToCycle < r.FromCycle || r.ToCycle-r.FromCycle >= constants.QUERY_MAX_CYCLES {
+		return query, fmt.Errorf("to_cycle has to be at least from_cycle and the range at most %d cycles", constants.QUERY_MAX_CYCLES)
+	}
+	if query.Limit == 0 {
+		query.Limit = constants.QUERY_DEFAULT_LIMIT
+	}
+	if query.Limit < 0 || query.Limit > constants.QUERY_MAX_LIMIT {
+		return query, fmt.Errorf("limit has to be between 1 and %d", constants.QUERY_MAX_LIMIT)
+	}
+	for _, field := range r.Fields {
+		if !slices.Contains(queryKeyFields, field) && !slices.Contains(querySelectedFields, field) {
+			return query, fmt.Errorf("unknown field %q", field)
+		}
+	}
+	query.OmitBalances = len(r.Fields) > 0 && !slices.Contains(r.Fields, "balances")
+	if r.Cursor != "" {
+		after, err := store.ParseDelegationStateKey(r.Cursor)
+		if err != nil {
+			return query, err
+		}
+		query.After = after
+	}
+	return query, nil
+}
+
+// selectFields keeps the key fields and the requested fields of the encoded state
+func selectFields(state *core.QueriedDelegationState, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	var encoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return encoded, nil
+	}
+	for key := range encoded {
+		if !slices.Contains(queryKeyFields, key) && !slices.Contains(fields, key) {
+			delete(encoded, key)
+		}
+	}
+	return encoded, nil
+}
+
+func registerQueryDelegationStates(app *fiber.App, engine *core.Engine) {
+	app.Post("/v1/delegation-states/query", func(c *fiber.Ctx) error {
+		var request DelegationStatesQueryRequest
+		if err := json.Unmarshal(c.Body(), &request); err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+		query, err := request.toStoreQuery()
+		if err != nil {
+			return respondError(c, fiber.StatusBadRequest, err.Error())
+		}
+
+		page, err := engine.QueryDelegationStates(c.Context(), query)
+		if err != nil {
+			if errors.Is(err, constants.ErrInvalidCursor) || errors.Is(err, constants.ErrAmbiguousCycleRange) {
+				return respondError(c, fiber.StatusBadRequest, err.Error())
+			}
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		response := DelegationStatesQueryResponse{
+			States:     make([]map[string]json.RawMessage, 0, len(page.States)),
+			NextCursor: page.NextCursor,
+		}
+		for i := range page.States {
+			state, err := selectFields(&page.States[i], request.Fields)
+			if err != nil {
+				return respondError(c, fiber.StatusInternalServerError, err.Error())
+			}
+			response.States = append(response.States, state)
+		}
+		return c.JSON(response)
+	})
+}
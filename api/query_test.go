@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryRequestValidation(t *testing.T) {
+	baker := "mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL"
+	valid := DelegationStatesQueryRequest{Delegates: []string{baker}, FromCycle: 10, ToCycle: 12, Fields: []string{"status"}}
+
+	query, err := valid.toStoreQuery()
+	require.NoError(t, err)
+	assert.Equal(t, 100, query.Limit)
+	assert.True(t, query.OmitBalances)
+
+	for name, request := range map[string]DelegationStatesQueryRequest{
+		"no delegates":  {FromCycle: 10, ToCycle: 12},
+		"reverse range": {Delegates: []string{baker}, FromCycle: 12, ToCycle: 10},
+		"long range":    {Delegates: []string{baker}, FromCycle: 10, ToCycle: 60},
+		"unknown field": {Delegates: []string{baker}, FromCycle: 10, ToCycle: 12, Fields: []string{"password"}},
+		"limit":         {Delegates: []string{baker}, FromCycle: 10, ToCycle: 12, Limit: 5000},
+		"cursor":        {Delegates: []string{baker}, FromCycle: 10, ToCycle: 12, Cursor: "!"},
+	} {
+		_, err := request.toStoreQuery()
+		assert.Error(t, err, name)
+	}
+
+	key := &store.DelegationStateKey{Cycle: 10, Delegate: mavryk.MustParseAddress(baker)}
+	valid.Cursor = key.Encode()
+	query, err = valid.toStoreQuery()
+	require.NoError(t, err)
+	assert.Equal(t, key, query.After)
+}
+
+func TestSelectFields(t *testing.T) {
+	state := &core.QueriedDelegationState{
+		RequestedCycle: 12,
+		StoredDelegationState: &store.StoredDelegationState{
+			Delegate: store.Address{Address: mavryk.MustParseAddress("mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL")},
+			Cycle:    8,
+			Revision: 2,
+		},
+	}
+
+	selected, err := selectFields(state, []string{"revision"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"delegate", "cycle", "requested_cycle", "revision"}, keys(selected))
+	assert.Equal(t, json.RawMessage("2"), selected["revision"])
+
+	all, err := selectFields(state, nil)
+	require.NoError(t, err)
+	assert.Contains(t, all, "balances")
+}
+
+func keys(m map[string]json.RawMessage) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+// HeaderApiKey carries the api key, keys are not accepted in the query so they do not end up in logs
+const HeaderApiKey = "X-API-Key"
+
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// rateLimitStore counts hits of a key in fixed windows of the expiration
+type rateLimitStore interface {
+	Hit(key string, expiration time.Duration) (int, time.Time, error)
+}
+
+type rateLimitWindow struct {
+	hits      int
+	expiresAt time.Time
+}
+
+type memoryRateLimitStore struct {
+	mtx       sync.Mutex
+	windows   map[string]*rateLimitWindow
+	nextSweep time.Time
+}
+
+func newMemoryRateLimitStore() *memoryRateLimitStore {
+	return &memoryRateLimitStore{
+		windows: make(map[string]*rateLimitWindow),
+	}
+}
+
+func (s *memoryRateLimitStore) Hit(key string, expiration time.Duration) (int, time.Time, error) {
+	now := time.Now()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if now.After(s.nextSweep) {
+		for k, window := range s.windows {
+			if !window.expiresAt.After(now) {
+				delete(s.windows, k)
+			}
+		}
+		s.nextSweep = now.Add(expiration)
+	}
+
+	window, ok := s.windows[key]
+	if !ok || !window.expiresAt.After(now) {
+		window = &rateLimitWindow{expiresAt: now.Add(expiration)}
+		s.windows[key] = window
+	}
+	window.hits++
+	return window.hits, window.expiresAt, nil
+}
+
+// sharedRateLimitStore keeps the windows in the database so replicas enforce a common limit
+type sharedRateLimitStore struct {
+	engine      *core.Engine
+	mtx         sync.Mutex
+	nextCleanup time.Time
+}
+
+func (s *sharedRateLimitStore) Hit(key string, expiration time.Duration) (int, time.Time, error) {
+	s.cleanup()
+	return s.engine.HitRateLimit(key, expiration)
+}
+
+func (s *sharedRateLimitStore) cleanup() {
+	now := time.Now()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if now.Before(s.nextCleanup) {
+		return
+	}
+	s.nextCleanup = now.Add(constants.RATE_LIMIT_CLEANUP_INTERVAL_SECONDS * time.Second)
+	go func() {
+		if err := s.engine.DeleteExpiredRateLimits(); err != nil {
+			slog.Warn("failed to delete expired rate limits", "error", err.Error())
+		}
+	}()
+}
+
+// rateLimiter limits anonymous requests per ip with the configured defaults
+// and requests with an api key by the limit and daily quota of the key
+type rateLimiter struct {
+	max        int
+	expiration time.Duration
+	windows    rateLimitStore
+	engine     *core.Engine
+}
+
+func newRateLimiter(config *configuration.Runtime, engine *core.Engine) *rateLimiter {
+	var windows rateLimitStore = newMemoryRateLimitStore()
+	if config.RateLimit.Shared {
+		windows = &sharedRateLimitStore{engine: engine}
+	}
+	return &rateLimiter{
+		max:        config.RateLimit.Max,
+		expiration: time.Duration(config.RateLimit.Expiration) * time.Second,
+		windows:    windows,
+		engine:     engine,
+	}
+}
+
+func secondsUntil(t time.Time) string {
+	return strconv.FormatInt(int64(time.Until(t).Round(time.Second)/time.Second), 10)
+}
+
+// hit counts the request in the window and sets the rate limit headers, false is returned if the limit is exceeded
+func (l *rateLimiter) hit(c *fiber.Ctx, windowKey string, limit int, expiration time.Duration) (bool, error) {
+	hits, expiresAt, err := l.windows.Hit(windowKey, expiration)
+	if err != nil {
+		return false, err
+	}
+	c.Set(headerRateLimitLimit, strconv.Itoa(limit))
+	c.Set(headerRateLimitRemaining, strconv.Itoa(max(limit-hits, 0)))
+	c.Set(headerRateLimitReset, secondsUntil(expiresAt))
+	if hits > limit {
+		c.Set(fiber.HeaderRetryAfter, secondsUntil(expiresAt))
+		return false, nil
+	}
+	return true, nil
+}
+
+func (l *rateLimiter) handle(c *fiber.Ctx) error {
+	limit, expiration := l.max, l.expiration
+	windowKey := "ip:" + c.IP()
+
+	var apiKey *store.ApiKey
+	if key := c.Get(HeaderApiKey); key != "" {
+		var cached bool
+		apiKey, cached = l.engine.CachedApiKey(key)
+		if !cached || apiKey == nil {
+			// keys not known to be valid count against the ip before they are looked up,
+			// random keys can not bypass the anonymous limit
+			allowed, err := l.hit(c, windowKey, limit, expiration)
+			if err != nil {
+				return respondError(c, fiber.StatusInternalServerError, err.Error())
+			}
+			if !allowed {
+				return respondError(c, fiber.StatusTooManyRequests, "rate limit exceeded")
+			}
+		}
+		if !cached {
+			var err error
+			if apiKey, err = l.engine.GetApiKey(key); err != nil && !errors.Is(err, constants.ErrNotFound) {
+				return respondError(c, fiber.StatusInternalServerError, err.Error())
+			}
+		}
+		if apiKey == nil {
+			return respondError(c, fiber.StatusUnauthorized, "invalid or revoked api key")
+		}
+		windowKey = fmt.Sprintf("key:%d", apiKey.Id)
+		if apiKey.RateLimitMax > 0 {
+			limit = apiKey.RateLimitMax
+		}
+		if apiKey.RateLimitExpiration > 0 {
+			expiration = time.Duration(apiKey.RateLimitExpiration) * time.Second
+		}
+	}
+
+	allowed, err := l.hit(c, windowKey, limit, expiration)
+	if err != nil {
+		return respondError(c, fiber.StatusInternalServerError, err.Error())
+	}
+	if !allowed {
+		return respondError(c, fiber.StatusTooManyRequests, "rate limit exceeded")
+	}
+
+	if apiKey != nil {
+		// rejected requests do not count towards the quota
+		counted, err := l.engine.RecordApiKeyUsage(apiKey)
+		if err != nil {
+			return respondError(c, fiber.StatusInternalServerError, err.Error())
+		}
+		if !counted {
+			c.Set(fiber.HeaderRetryAfter, secondsUntil(time.Now().UTC().Truncate(24*time.Hour).Add(24*time.Hour)))
+			return respondError(c, fiber.StatusTooManyRequests, "daily quota exceeded")
+		}
+	}
+	return c.Next()
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	s := newMemoryRateLimitStore()
+	for i := 1; i <= 3; i++ {
+		hits, _, err := s.Hit("ip:127.0.0.1", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, i, hits)
+	}
+	hits, _, _ := s.Hit("ip:127.0.0.2", time.Minute)
+	assert.Equal(t, 1, hits)
+
+	s.windows["ip:127.0.0.1"].expiresAt = time.Now().Add(-time.Second)
+	hits, _, _ = s.Hit("ip:127.0.0.1", time.Minute)
+	assert.Equal(t, 1, hits)
+}
+
+func TestAnonymousRateLimit(t *testing.T) {
+	config := &configuration.Runtime{}
+	config.RateLimit.Max = 2
+	config.RateLimit.Expiration = 60
+	app, err := newPublicApp(config, nil)
+	require.NoError(t, err)
+	// an invalid cycle is rejected before the engine is used
+	target := "/delegate/abc/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL"
+
+	for i := 0; i < 2; i++ {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	}
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get(headerRateLimitRemaining))
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+
+	var body ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "rate limit exceeded", body.Error)
+}
+
+func TestReloadKeepsLimiterWithUnchangedLimits(t *testing.T) {
+	config := &configuration.Runtime{}
+	config.RateLimit.Max = 2
+	config.RateLimit.Expiration = 60
+	_, err := newPublicApp(config, nil)
+	require.NoError(t, err)
+	handler := publicLimiter.Load()
+
+	ReloadPublicApi(config, nil)
+	assert.Same(t, handler, publicLimiter.Load())
+
+	config.RateLimit.Max = 3
+	ReloadPublicApi(config, nil)
+	assert.NotSame(t, handler, publicLimiter.Load())
+}
+
+func TestApiKeyNotAcceptedInQuery(t *testing.T) {
+	config := &configuration.Runtime{}
+	config.RateLimit.Max = 1
+	config.RateLimit.Expiration = 60
+	app, err := newPublicApp(config, nil)
+	require.NoError(t, err)
+
+	// the key is ignored so the request is limited per ip without looking the key up
+	target := "/delegate/abc/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL?api_key=pr_unknown"
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+}
@@ -0,0 +1,429 @@
+// Package client is a typed client of the public and private api, see api/openapi for the specification.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/merkle"
+	"github.com/mavryk-network/protocol-rewards/signing"
+	"github.com/samber/lo"
+)
+
+// Error is returned for non 2xx responses. Not found, conflict and gone responses
+// also match constants.ErrNotFound, constants.ErrCommitmentOutdated and constants.ErrCyclePruned with errors.Is.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d: %s", e.StatusCode, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return constants.ErrNotFound
+	case http.StatusConflict:
+		return constants.ErrCommitmentOutdated
+	case http.StatusGone:
+		return constants.ErrCyclePruned
+	default:
+		return nil
+	}
+}
+
+// HeaderApiKey carries the api key
+const HeaderApiKey = "X-API-Key"
+
+type Client struct {
+	baseUrl string
+	http    *http.Client
+	signer  *mavryk.Address
+	apiKey  string
+	token   string
+}
+
+type Option func(c *Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = httpClient
+	}
+}
+
+// WithSigner requires signed endpoints to be signed by the signer, responses failing verification are rejected
+func WithSigner(signer mavryk.Address) Option {
+	return func(c *Client) {
+		c.signer = &signer
+	}
+}
+
+// WithApiKey sends the key with every request, public api requests are then limited by the quota of the key
+func WithApiKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithToken authenticates private api requests with the bearer token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// New creates a client of the api at baseUrl, e.g. http://127.0.0.1:3000 for the public
+// or http://127.0.0.1:4000 for the private one
+func New(baseUrl string, options ...Option) *Client {
+	c := &Client{
+		baseUrl: strings.TrimSuffix(baseUrl, "/"),
+		http:    http.DefaultClient,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+func (c *Client) do(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, withQuery(c.baseUrl+path, query), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.send(req)
+}
+
+func (c *Client) post(ctx context.Context, path string, body any, result any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseUrl+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.apiKey != "" {
+		req.Header.Set(HeaderApiKey, c.apiKey)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var body ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == "" {
+			body.Error = http.StatusText(resp.StatusCode)
+		}
+		return nil, &Error{StatusCode: resp.StatusCode, Message: body.Error}
+	}
+	return resp, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, result any) error {
+	resp, err := c.do(ctx, path, query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// getSigned verifies the signature headers against the pinned signer before decoding
+// and checks the response was signed for the requested delegate and cycle
+func (c *Client) getSigned(ctx context.Context, path string, subject *signing.Subject, result any) (int, error) {
+	resp, err := c.do(ctx, path, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, nil
+	}
+	if c.signer != nil {
+		envelope, err := signing.EnvelopeFromResponse(resp.Header, body)
+		if err != nil {
+			return resp.StatusCode, err
+		}
+		if err := envelope.Verify(*c.signer, subject); err != nil {
+			return resp.StatusCode, err
+		}
+	}
+	return resp.StatusCode, json.Unmarshal(body, result)
+}
+
+func cyclePath(format string, cycle int64, address mavryk.Address) string {
+	return fmt.Sprintf(format, cycle, address.String())
+}
+
+// public api
+
+func (c *Client) GetDelegationState(ctx context.Context, delegate mavryk.Address, cycle int64) (*StoredDelegationState, error) {
+	var result StoredDelegationState
+	if _, err := c.getSigned(ctx, cyclePath("/delegate/%d/%s", cycle, delegate), signing.NewSubject(delegate, cycle), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) IsDelegationStateAvailable(ctx context.Context, delegate mavryk.Address, cycle int64) (bool, error) {
+	var result bool
+	err := c.get(ctx, cyclePath("/delegate/%d/%s/available", cycle, delegate), nil, &result)
+	return result, err
+}
+
+func (c *Client) GetTopDelegators(ctx context.Context, delegate mavryk.Address, cycle int64, limit int) ([]DelegationStateBalance, error) {
+	var result []DelegationStateBalance
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	err := c.get(ctx, cyclePath("/delegate/%d/%s/top", cycle, delegate), query, &result)
+	return result, err
+}
+
+func (c *Client) GetDelegationStateRevisions(ctx context.Context, delegate mavryk.Address, cycle int64) ([]DelegationStateRevision, error) {
+	var result []DelegationStateRevision
+	err := c.get(ctx, cyclePath("/delegate/%d/%s/revisions", cycle, delegate), nil, &result)
+	return result, err
+}
+
+// DiffDelegationStateRevisions compares two revisions, toRevision 0 means the current one
+func (c *Client) DiffDelegationStateRevisions(ctx context.Context, delegate mavryk.Address, cycle, fromRevision, toRevision int64) (*DelegationStateRevisionDiff, error) {
+	var result DelegationStateRevisionDiff
+	query := url.Values{"from": {strconv.FormatInt(fromRevision, 10)}}
+	if toRevision > 0 {
+		query.Set("to", strconv.FormatInt(toRevision, 10))
+	}
+	if err := c.get(ctx, cyclePath("/delegate/%d/%s/revisions/diff", cycle, delegate), query, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DiffDelegationStateCycles lists delegators which joined, left or changed between the cycles
+func (c *Client) DiffDelegationStateCycles(ctx context.Context, delegate mavryk.Address, fromCycle, toCycle int64) (*DelegationStateCycleDiff, error) {
+	var result DelegationStateCycleDiff
+	if err := c.get(ctx, fmt.Sprintf("/delegate/%s/diff/%d/%d", delegate.String(), fromCycle, toCycle), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetOverstakeAnalytics returns constants.ErrNotFound if the state is missing or was stored without staking parameters
+func (c *Client) GetOverstakeAnalytics(ctx context.Context, delegate mavryk.Address, cycle int64) (*OverstakeAnalytics, error) {
+	var result OverstakeAnalytics
+	if err := c.get(ctx, cyclePath("/delegate/%d/%s/overstake", cycle, delegate), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetDelegateHistory returns the stake composition of the baker per cycle in range [fromCycle, toCycle]
+func (c *Client) GetDelegateHistory(ctx context.Context, delegate mavryk.Address, fromCycle, toCycle int64) ([]DelegateCycleRecord, error) {
+	query := url.Values{}
+	query.Set("from", strconv.FormatInt(fromCycle, 10))
+	query.Set("to", strconv.FormatInt(toCycle, 10))
+	var result []DelegateCycleRecord
+	if err := c.get(ctx, fmt.Sprintf("/delegate/%s/history", delegate.String()), query, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) GetDelegatorBalances(ctx context.Context, delegator mavryk.Address, cycle int64) ([]DelegationStateBalance, error) {
+	var result []DelegationStateBalance
+	err := c.get(ctx, cyclePath("/delegator/%d/%s", cycle, delegator), nil, &result)
+	return result, err
+}
+
+func versionQuery(version int64) url.Values {
+	if version <= 0 {
+		return nil
+	}
+	return url.Values{"version": {strconv.FormatInt(version, 10)}}
+}
+
+// GetCycleRoot returns the version of the cycle commitment, 0 means the latest version
+func (c *Client) GetCycleRoot(ctx context.Context, cycle, version int64) (*CycleSummary, error) {
+	var result CycleSummary
+	if err := c.get(ctx, fmt.Sprintf("/cycle/%d/root", cycle), versionQuery(version), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetDelegatorProofs returns proofs against the version of the cycle commitment, 0 means the latest version
+func (c *Client) GetDelegatorProofs(ctx context.Context, delegator mavryk.Address, cycle, version int64) ([]merkle.InclusionProof, error) {
+	var result []merkle.InclusionProof
+	err := c.get(ctx, fmt.Sprintf("/delegator/%s/%d/proof", delegator.String(), cycle), versionQuery(version), &result)
+	return result, err
+}
+
+func (c *Client) GetStatistics(ctx context.Context, cycle int64) (*CycleStatistics, error) {
+	var result CycleStatistics
+	if err := c.get(ctx, fmt.Sprintf("/statistics/%d", cycle), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRewardsSplit returns constants.ErrMinimumDelegatedBalanceNotFound if the minimum of the cycle is not available
+func (c *Client) GetRewardsSplit(ctx context.Context, delegate mavryk.Address, cycle int64) (*MvktLikeDelegationState, error) {
+	var result MvktLikeDelegationState
+	status, err := c.getSigned(ctx, fmt.Sprintf("/v1/rewards/split/%s/%d", delegate.String(), cycle), signing.NewSubject(delegate, cycle), &result)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNoContent {
+		return nil, constants.ErrMinimumDelegatedBalanceNotFound
+	}
+	return &result, nil
+}
+
+// QueryDelegationStates returns a single page, pass NextCursor of the response as Cursor for the next one
+func (c *Client) QueryDelegationStates(ctx context.Context, request *DelegationStatesQueryRequest) (*DelegationStatesQueryResponse, error) {
+	var result DelegationStatesQueryResponse
+	if err := c.post(ctx, "/v1/delegation-states/query", request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// private api
+
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+func fetchQuery(force bool) url.Values {
+	if !force {
+		return nil
+	}
+	return url.Values{"force": {"true"}}
+}
+
+func (c *Client) FetchCycle(ctx context.Context, cycle int64, force bool) (*FetchResponse, error) {
+	var result FetchResponse
+	if err := c.post(ctx, withQuery(fmt.Sprintf("/fetch/cycle/%d", cycle), fetchQuery(force)), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) FetchDelegate(ctx context.Context, delegate mavryk.Address, cycle int64, force bool) (*FetchResponse, error) {
+	var result FetchResponse
+	if err := c.post(ctx, withQuery(cyclePath("/fetch/delegate/%d/%s", cycle, delegate), fetchQuery(force)), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Prune applies the retention policy relative to the cycle, 0 means the last fetched cycle
+func (c *Client) Prune(ctx context.Context, cycle int64) (*RetentionReport, error) {
+	query := url.Values{}
+	if cycle > 0 {
+		query.Set("cycle", strconv.FormatInt(cycle, 10))
+	}
+	var result RetentionReport
+	if err := c.post(ctx, withQuery("/prune", query), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListAuditEntries returns the latest entries, pass the lowest id as before for older ones
+func (c *Client) ListAuditEntries(ctx context.Context, limit int, before int64) ([]AuditEntry, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if before > 0 {
+		query.Set("before", strconv.FormatInt(before, 10))
+	}
+	var result []AuditEntry
+	err := c.get(ctx, "/audit", query, &result)
+	return result, err
+}
+
+func (c *Client) ExplainDelegationState(ctx context.Context, delegate mavryk.Address, cycle int64) (*DelegationStateExplanation, error) {
+	var result DelegationStateExplanation
+	if err := c.get(ctx, cyclePath("/explain/%d/%s", cycle, delegate), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Export streams the export in the format, the caller has to close the returned reader
+func (c *Client) Export(ctx context.Context, filter *ExportFilter, format ExportFormat) (io.ReadCloser, error) {
+	if filter == nil {
+		return nil, errors.New("export filter is required")
+	}
+	query := url.Values{
+		"from":   {strconv.FormatInt(filter.FromCycle, 10)},
+		"to":     {strconv.FormatInt(filter.ToCycle, 10)},
+		"format": {string(format)},
+	}
+	if len(filter.Delegates) > 0 {
+		query.Set("delegates", strings.Join(lo.Map(filter.Delegates, func(addr mavryk.Address, _ int) string { return addr.String() }), ","))
+	}
+	resp, err := c.do(ctx, "/export", query)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) ListApiKeys(ctx context.Context) ([]ApiKey, error) {
+	var result []ApiKey
+	err := c.get(ctx, "/keys", nil, &result)
+	return result, err
+}
+
+// CreateApiKey returns the created key, its Key is the secret to hand out
+func (c *Client) CreateApiKey(ctx context.Context, request *CreateApiKeyRequest) (*CreateApiKeyResponse, error) {
+	var result CreateApiKeyResponse
+	if err := c.post(ctx, "/keys", request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) RevokeApiKey(ctx context.Context, id int64) error {
+	return c.post(ctx, fmt.Sprintf("/keys/%d/revoke", id), nil, nil)
+}
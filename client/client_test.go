@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cycle/1/root":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Cycle commitment not found"}`))
+		case "/v1/rewards/split/mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL/1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+	c := New(server.URL + "/")
+
+	_, err := c.GetCycleRoot(context.Background(), 1, 0)
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "Cycle commitment not found", apiErr.Message)
+	assert.ErrorIs(t, err, constants.ErrNotFound)
+
+	_, err = c.GetRewardsSplit(context.Background(), mavryk.MustParseAddress("mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL"), 1)
+	assert.ErrorIs(t, err, constants.ErrMinimumDelegatedBalanceNotFound)
+
+	_, err = c.GetStatistics(context.Background(), 1)
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.NotErrorIs(t, err, constants.ErrNotFound)
+}
@@ -0,0 +1,351 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+)
+
+// Response types mirror the schemas of api/openapi, they are kept apart from the server
+// packages so the client does not depend on the database, rpc or http server stack.
+
+// ErrorResponse is the body of every non successful response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+type DelegationStateStatus int
+
+const (
+	DelegationStateStatusOk                  DelegationStateStatus = iota
+	DelegationStateStatusMinimumNotAvailable                       // 1
+)
+
+func (s DelegationStateStatus) String() string {
+	switch s {
+	case DelegationStateStatusOk:
+		return "ok"
+	case DelegationStateStatusMinimumNotAvailable:
+		return "minimum_not_available"
+	default:
+		return "unknown"
+	}
+}
+
+// RevisionReason records why a revision of a delegation state was written
+type RevisionReason string
+
+type DelegatorBalances struct {
+	DelegatedBalance int64 `json:"delegated_balance"`
+	// portion of staked balance included in delegated balance
+	OverstakedBalance int64 `json:"overstaked_balance"`
+	StakedBalance     int64 `json:"staked_balance"`
+}
+
+type StakingParameters struct {
+	LimitOfStakingOverBakingMillionth int64 `json:"limit_of_staking_over_baking_millionth"`
+	EdgeOfBakingOverStakingBillionth  int64 `json:"edge_of_baking_over_staking_billionth"`
+}
+
+type StoredDelegationState struct {
+	Delegate mavryk.Address                       `json:"delegate"`
+	Cycle    int64                                `json:"cycle"`
+	Status   DelegationStateStatus                `json:"status"`
+	Balances map[mavryk.Address]DelegatorBalances `json:"balances"`
+	// missing for states stored before staking parameters were kept
+	StakingParameters *StakingParameters `json:"staking_parameters,omitempty"`
+	Revision          int64              `json:"revision"`
+	Reason            RevisionReason     `json:"reason"`
+	StoredAt          time.Time          `json:"stored_at"`
+}
+
+// DelegationStateBalance is a single delegator balance of a baker
+type DelegationStateBalance struct {
+	Cycle             int64          `json:"cycle"`
+	Baker             mavryk.Address `json:"baker"`
+	Delegator         mavryk.Address `json:"delegator"`
+	DelegatedBalance  int64          `json:"delegated_balance"`
+	StakedBalance     int64          `json:"staked_balance"`
+	OverstakedBalance int64          `json:"overstaked_balance"`
+}
+
+// DelegationStateRevision is a stored or replaced revision of a delegation state
+type DelegationStateRevision struct {
+	Revision   int64                 `json:"revision"`
+	Status     DelegationStateStatus `json:"status"`
+	Reason     RevisionReason        `json:"reason"`
+	StoredAt   time.Time             `json:"stored_at"`
+	ReplacedAt *time.Time            `json:"replaced_at"`
+	Current    bool                  `json:"current"`
+	Delegators int                   `json:"delegators"`
+}
+
+// DelegatorBalanceChange has a nil From or To if the delegator is missing on that side
+type DelegatorBalanceChange struct {
+	Delegator             mavryk.Address     `json:"delegator"`
+	From                  *DelegatorBalances `json:"from"`
+	To                    *DelegatorBalances `json:"to"`
+	DelegatedBalanceDiff  int64              `json:"delegated_balance_diff"`
+	StakedBalanceDiff     int64              `json:"staked_balance_diff"`
+	OverstakedBalanceDiff int64              `json:"overstaked_balance_diff"`
+}
+
+type DelegationStateRevisionDiff struct {
+	Delegate     mavryk.Address           `json:"delegate"`
+	Cycle        int64                    `json:"cycle"`
+	FromRevision int64                    `json:"from_revision"`
+	ToRevision   int64                    `json:"to_revision"`
+	Changes      []DelegatorBalanceChange `json:"changes"`
+}
+
+type DelegationStateCycleDiffTotals struct {
+	Added                  int   `json:"added"`
+	Removed                int   `json:"removed"`
+	Changed                int   `json:"changed"`
+	JoinedDelegatedBalance int64 `json:"joined_delegated_balance"`
+	JoinedStakedBalance    int64 `json:"joined_staked_balance"`
+	LeftDelegatedBalance   int64 `json:"left_delegated_balance"`
+	LeftStakedBalance      int64 `json:"left_staked_balance"`
+	DelegatedBalanceDiff   int64 `json:"delegated_balance_diff"`
+	StakedBalanceDiff      int64 `json:"staked_balance_diff"`
+	OverstakedBalanceDiff  int64 `json:"overstaked_balance_diff"`
+}
+
+// DelegationStateCycleDiff compares the delegators of a delegate in two cycles
+type DelegationStateCycleDiff struct {
+	Delegate  mavryk.Address                 `json:"delegate"`
+	FromCycle int64                          `json:"from_cycle"`
+	ToCycle   int64                          `json:"to_cycle"`
+	Added     []mavryk.Address               `json:"added"`
+	Removed   []mavryk.Address               `json:"removed"`
+	Changes   []DelegatorBalanceChange       `json:"changes"`
+	Totals    DelegationStateCycleDiffTotals `json:"totals"`
+}
+
+// OverstakeAnalytics describes how close the baker is to its staking limit, balances are in mumav
+type OverstakeAnalytics struct {
+	Baker                    mavryk.Address    `json:"baker"`
+	Cycle                    int64             `json:"cycle"`
+	StakingParameters        StakingParameters `json:"staking_parameters"`
+	OwnStakedMumav           int64             `json:"own_staked_mumav"`
+	StakingLimitMumav        int64             `json:"staking_limit_mumav"`
+	ExternalStakedMumav      int64             `json:"external_staked_mumav"`
+	OverstakedMumav          int64             `json:"overstaked_mumav"`
+	OverstakeFactorMillionth int64             `json:"overstake_factor_millionth"`
+	SpareCapacityMumav       int64             `json:"spare_capacity_mumav"`
+	SpareCapacityMav         string            `json:"spare_capacity_mav"`
+	// nil if the baker accepts no external stake
+	OwnStakeToAbsorbMumav *int64  `json:"own_stake_to_absorb_mumav"`
+	OwnStakeToAbsorbMav   *string `json:"own_stake_to_absorb_mav"`
+}
+
+// DelegateCycleRecord is the stake composition of a baker in a single cycle
+type DelegateCycleRecord struct {
+	Cycle              int64  `json:"cycle"`
+	OwnDelegated       int64  `json:"own_delegated"`
+	OwnStaked          int64  `json:"own_staked"`
+	ExternalDelegated  int64  `json:"external_delegated"`
+	ExternalStaked     int64  `json:"external_staked"`
+	ExternalOverstaked int64  `json:"external_overstaked"`
+	Delegators         int64  `json:"delegators"`
+	BakingPower        int64  `json:"baking_power"`
+	Status             string `json:"status,omitempty"`
+}
+
+// CycleSummary is a version of the cycle commitment
+type CycleSummary struct {
+	Cycle      int64     `json:"cycle"`
+	Version    int64     `json:"version"`
+	MerkleRoot string    `json:"merkle_root"`
+	Leaves     int64     `json:"leaves"`
+	Bakers     int64     `json:"bakers"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+type DelegatorSizeStatistics struct {
+	Min    int64 `json:"min"`
+	Median int64 `json:"median"`
+	Max    int64 `json:"max"`
+}
+
+type DelegateCycleStatistics struct {
+	ExternalStaked     int64                   `json:"external_staked"`
+	OwnStaked          int64                   `json:"own_staked"`
+	ExternalDelegated  int64                   `json:"external_delegated"`
+	OwnDelegated       int64                   `json:"own_delegated"`
+	ExternalOverstaked int64                   `json:"external_overstaked"`
+	Delegators         int                     `json:"delegators"`
+	Stakers            int                     `json:"stakers"`
+	BakingPower        int64                   `json:"baking_power"`
+	NetworkShare       float64                 `json:"network_share"`
+	DelegatorSizes     DelegatorSizeStatistics `json:"delegator_sizes"`
+	Status             string                  `json:"status,omitempty"`
+}
+
+type NetworkCycleStatistics struct {
+	Bakers         int                     `json:"bakers"`
+	Delegators     int                     `json:"delegators"`
+	Stakers        int                     `json:"stakers"`
+	Staked         int64                   `json:"staked"`
+	Delegated      int64                   `json:"delegated"`
+	Overstaked     int64                   `json:"overstaked"`
+	BakingPower    int64                   `json:"baking_power"`
+	DelegatorSizes DelegatorSizeStatistics `json:"delegator_sizes"`
+	Statuses       map[string]int          `json:"statuses"`
+}
+
+type CycleStatistics struct {
+	Cycle     int64                                      `json:"cycle"`
+	Delegates map[mavryk.Address]DelegateCycleStatistics `json:"delegates"`
+	Network   NetworkCycleStatistics                     `json:"network"`
+}
+
+type MvktDelegator struct {
+	Address          mavryk.Address `json:"address"`
+	DelegatedBalance int64          `json:"delegatedBalance"`
+	StakedBalance    int64          `json:"stakedBalance"`
+}
+
+type MvktLikeDelegationState struct {
+	Cycle                    int64           `json:"cycle"`
+	OwnDelegatedBalance      int64           `json:"ownDelegatedBalance"`
+	OwnStakedBalance         int64           `json:"ownStakedBalance"`
+	ExternalDelegatedBalance int64           `json:"externalDelegatedBalance"`
+	ExternalStakedBalance    int64           `json:"externalStakedBalance"`
+	DelegatorsCount          int             `json:"delegatorsCount"`
+	Delegators               []MvktDelegator `json:"delegators"`
+}
+
+type DelegationStatesQueryRequest struct {
+	Delegates []string `json:"delegates"`
+	FromCycle int64    `json:"from_cycle"`
+	ToCycle   int64    `json:"to_cycle"`
+	// fields of the states to return, all if empty. delegate, cycle and requested_cycle are always returned.
+	Fields []string `json:"fields,omitempty"`
+	Cursor string   `json:"cursor,omitempty"`
+	Limit  int      `json:"limit,omitempty"`
+}
+
+// DelegationStatesQueryResponse carries the selected fields of every state as raw json
+type DelegationStatesQueryResponse struct {
+	States     []map[string]json.RawMessage `json:"states"`
+	NextCursor string                       `json:"next_cursor,omitempty"`
+}
+
+// private api
+
+// FetchResponse acknowledges a fetch which continues in the background
+type FetchResponse struct {
+	Cycle   int64  `json:"cycle"`
+	Address string `json:"address,omitempty"`
+}
+
+type RetentionReport struct {
+	Cycle    int64   `json:"cycle"`
+	Pruned   []int64 `json:"pruned"`
+	Archived []int64 `json:"archived"`
+}
+
+type AuditEntry struct {
+	Id            int64     `json:"id"`
+	Time          time.Time `json:"time"`
+	Actor         string    `json:"actor"`
+	Role          string    `json:"role"`
+	Action        string    `json:"action"`
+	Request       string    `json:"request"`
+	RemoteAddress string    `json:"remote_address"`
+	Status        int       `json:"status"`
+}
+
+type ApiKey struct {
+	Id                  int64      `json:"id"`
+	Name                string     `json:"name"`
+	Prefix              string     `json:"prefix"`
+	RateLimitMax        int        `json:"rate_limit_max"`
+	RateLimitExpiration int        `json:"rate_limit_expiration"`
+	DailyQuota          int64      `json:"daily_quota"`
+	Requests            int64      `json:"requests"`
+	RequestsToday       int64      `json:"requests_today"`
+	LastUsedAt          *time.Time `json:"last_used_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	RevokedAt           *time.Time `json:"revoked_at"`
+}
+
+// CreateApiKeyRequest creates a key, zero rate limit fields fall back to the configured defaults
+type CreateApiKeyRequest struct {
+	Name                string `json:"name"`
+	RateLimitMax        int    `json:"rate_limit_max"`
+	RateLimitExpiration int    `json:"rate_limit_expiration"`
+	DailyQuota          int64  `json:"daily_quota"`
+}
+
+// CreateApiKeyResponse carries the secret key, it is not retrievable later
+type CreateApiKeyResponse struct {
+	ApiKey
+	Key string `json:"key"`
+}
+
+type BalanceInfo struct {
+	Balance         int64          `json:"balance"`
+	StakedBalance   int64          `json:"frozen_deposits"`
+	UnstakedBalance int64          `json:"unfrozen_deposits"`
+	Baker           mavryk.Address `json:"delegate"`
+	StakeBaker      mavryk.Address `json:"stake_baker"`
+}
+
+type CreationInfo struct {
+	Level         int64         `json:"level"`
+	Operation     mavryk.OpHash `json:"operation"`
+	Index         int           `json:"transaction_index"`
+	InternalIndex int           `json:"internal_result_index"`
+	Kind          string        `json:"kind"`
+}
+
+type ExplainedBalanceUpdate struct {
+	Address       mavryk.Address `json:"address"`
+	Amount        int64          `json:"amount,string"`
+	Kind          string         `json:"kind"`
+	Category      string         `json:"category"`
+	Operation     mavryk.OpHash  `json:"operation"`
+	Index         int            `json:"index"`
+	InternalIndex int            `json:"internal_index"`
+	Source        string         `json:"source"`
+	Delegate      mavryk.Address `json:"delegate"`
+	Deferred      string         `json:"deferred,omitempty"`
+	Applied       bool           `json:"applied"`
+	SkipReason    string         `json:"skip_reason,omitempty"`
+	// running delegated total after the update
+	DelegatedBalance int64 `json:"delegated_balance"`
+	Diff             int64 `json:"diff"`
+}
+
+// DelegationStateExplanation is a trace of the delegation state reconstruction
+type DelegationStateExplanation struct {
+	Delegate                mavryk.Address                 `json:"delegate"`
+	Cycle                   int64                          `json:"cycle"`
+	LastBlockLevel          int64                          `json:"last_block_level"`
+	BlockWithMinimum        int64                          `json:"block_with_minimum"`
+	TargetAmount            int64                          `json:"target_amount"`
+	InitialBalances         map[mavryk.Address]BalanceInfo `json:"initial_balances"`
+	InitialDelegatedBalance int64                          `json:"initial_delegated_balance"`
+	Updates                 []ExplainedBalanceUpdate       `json:"updates"`
+	Matched                 bool                           `json:"matched"`
+	MatchedUpdateIndex      *int                           `json:"matched_update_index"`
+	CreatedAt               CreationInfo                   `json:"created_at"`
+	Error                   string                         `json:"error,omitempty"`
+}
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatJSONL   ExportFormat = "jsonl"
+	ExportFormatParquet ExportFormat = "parquet"
+)
+
+type ExportFilter struct {
+	FromCycle int64
+	ToCycle   int64
+	Delegates []mavryk.Address
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/samber/lo"
+)
+
+func formatBackfillReport(report *core.BackfillReport) string {
+	lines := []string{fmt.Sprintf("backfill %s of cycles %d - %d: %d fetched, %d completed by previous runs", report.Job, report.From, report.To, len(report.Cycles), len(report.Resumed))}
+
+	failedCycles := lo.Keys(report.Errors)
+	sort.Slice(failedCycles, func(i, j int) bool { return failedCycles[i] < failedCycles[j] })
+	for _, cycle := range failedCycles {
+		lines = append(lines, fmt.Sprintf("  cycle %d failed: %s", cycle, report.Errors[cycle]))
+	}
+	for _, result := range report.Cycles {
+		for _, failure := range result.Failures {
+			lines = append(lines, fmt.Sprintf("  cycle %d delegate %s failed: %s", result.Cycle, failure.Delegate.String(), failure.Error))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func runBackfill(ctx context.Context, args []string) int {
+	flags := newFlagSet("backfill", "backfill -from <cycle> -to <cycle> [flags]", true)
+	from := flags.fs.Int64("from", -1, "first cycle to fetch")
+	to := flags.fs.Int64("to", -1, "last cycle to fetch")
+	delegates := flags.fs.String("delegates", "", "comma separated list of delegates to fetch, all (respecting the configured filter) if empty")
+	parallelism := flags.fs.Int("parallel", constants.BACKFILL_CYCLE_BATCH_SIZE, "number of cycles fetched in parallel")
+	force := flags.fs.Bool("force", false, "fetch even if the delegation state is already stored")
+	restart := flags.fs.Bool("restart", false, "drop the progress of previous runs and start over")
+	if _, err := flags.parse(args); err != nil {
+		return EXIT_USAGE
+	}
+	if *from < 0 || *to < *from {
+		return flags.usageError(errors.New("-from and -to are required and -from has to be less or equal to -to"))
+	}
+	if *parallelism <= 0 {
+		return flags.usageError(errors.New("-parallel has to be positive"))
+	}
+	delegateFilter, err := parseAddresses(*delegates)
+	if err != nil {
+		return flags.usageError(err)
+	}
+
+	engine, code := newEngine(ctx, flags)
+	if engine == nil {
+		return code
+	}
+
+	report, err := engine.Backfill(ctx, &core.BackfillOptions{
+		FromCycle:   *from,
+		ToCycle:     *to,
+		Delegates:   delegateFilter,
+		Parallelism: *parallelism,
+		Force:       *force,
+		Restart:     *restart,
+	})
+	if report == nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+
+	flags.print(report, func() string {
+		return formatBackfillReport(report)
+	})
+	if err != nil || report.HasFailures() {
+		return EXIT_FAILURE
+	}
+	return EXIT_OK
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/test"
+)
+
+const (
+	EXIT_OK      = 0
+	EXIT_FAILURE = 1
+	EXIT_USAGE   = 2
+	EXIT_CONFIG  = 3
+)
+
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(ctx context.Context, args []string) int
+}
+
+func getCommands() []command {
+	return []command{
+		{"serve", "serve [flags]", "run the service with public and private api (default)", runServe},
+		{"fetch", "fetch cycle <cycle> | fetch delegate <address> <cycle> [flags]", "fetch and store delegation states", runFetch},
+		{"backfill", "backfill -from <cycle> -to <cycle> [flags]", "fetch delegation states of a range of cycles", runBackfill},
+		{"explain", "explain <address> <cycle> [flags]", "show how the delegation state is reconstructed without storing it", runExplain},
+		{"verify", "verify cycle <cycle> | verify delegate <address> <cycle> [flags]", "recompute delegation states and compare them with the stored ones", runVerify},
+		{"signature", "signature verify <url | envelope file> -signer <address> | signature address [flags]", "verify signed responses or print the configured signer", runSignature},
+		{"export", "export -from <cycle> -to <cycle> [flags]", "export stored delegation states", runExport},
+		{"snapshot", "snapshot export -from <cycle> -to <cycle> -o <file> | snapshot import <file> [flags]", "create or import a portable snapshot of stored delegation states", runSnapshot},
+		{"prune", "prune [-cycle <cycle>] [flags]", "apply the retention policy of the storage configuration", runPrune},
+		{"keys", "keys create -name <name> | keys list | keys revoke <id> [flags]", "manage api keys of the public api", runKeys},
+		{"migrate", "migrate [status | up | down] [flags]", "show, apply or revert database migrations", runMigrate},
+		{"config", "config check [flags]", "validate the configuration", runConfig},
+		{"version", "version [flags]", "print version", runVersion},
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s <command> [flags] [arguments]\n\nCommands:\n", os.Args[0])
+	for _, c := range getCommands() {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.description)
+		fmt.Fprintf(os.Stderr, "  %-10s   %s %s\n", "", os.Args[0], c.usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for command flags.\n", os.Args[0])
+}
+
+// Run executes the command selected by args and returns the process exit code
+func Run(args []string) int {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	// without command we serve, flags are passed to serve for backward compatibility
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	switch name {
+	case "serve":
+		for _, arg := range args {
+			if arg == "-version" || arg == "--version" {
+				return runVersion(ctx, nil)
+			}
+		}
+	case "help", "-h", "--help":
+		usage()
+		return EXIT_OK
+	}
+
+	for _, c := range getCommands() {
+		if c.name == name {
+			return c.run(ctx, args)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	return EXIT_USAGE
+}
+
+type globalFlags struct {
+	fs         *flag.FlagSet
+	configPath *string
+	logLevel   *string
+	json       *bool
+	cache      *string
+}
+
+func newFlagSet(name, usageLine string, withCache bool) *globalFlags {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	result := &globalFlags{
+		fs:         fs,
+		configPath: fs.String("config", "config.hjson", "path to the configuration file"),
+		logLevel:   fs.String("log", "", "set the desired log level (debug, info, warn, error)"),
+		json:       fs.Bool("json", false, "print results and logs as json"),
+	}
+	if withCache {
+		result.cache = fs.String("cache", "", "cache id, e.g. test/data/745, responses are served from <cache> and <cache>.gob.lz4 when available")
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s\n", os.Args[0], usageLine)
+		fs.PrintDefaults()
+	}
+	return result
+}
+
+// parse parses flags placed anywhere between the positional arguments
+func (g *globalFlags) parse(args []string) ([]string, error) {
+	positional := make([]string, 0)
+	for {
+		if err := g.fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = g.fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if *g.json {
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+	}
+	return positional, nil
+}
+
+func (g *globalFlags) applyLogLevel(config *configuration.Runtime) {
+	if *g.logLevel != "" {
+		config.LogLevel = configuration.GetLogLevel(*g.logLevel)
+	}
+	slog.SetLogLoggerLevel(config.LogLevel)
+	if *g.json {
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: config.LogLevel})))
+	}
+}
+
+func (g *globalFlags) loadConfiguration() (*configuration.Runtime, error) {
+	config, err := configuration.LoadConfiguration(*g.configPath)
+	if err != nil {
+		return nil, err
+	}
+	g.applyLogLevel(config)
+	return config, nil
+}
+
+func (g *globalFlags) engineOptions(base *core.EngineOptions) (*core.EngineOptions, error) {
+	options := *base
+	if g.cache == nil || *g.cache == "" {
+		return &options, nil
+	}
+
+	transport, err := test.NewTestTransport(http.DefaultTransport, *g.cache, *g.cache+".gob.lz4")
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("using caching transport", "cacheId", *g.cache)
+	options.Transport = transport
+	return &options, nil
+}
+
+// fail reports the error in the selected output mode and returns the exit code
+func (g *globalFlags) fail(code int, err error) int {
+	if *g.json {
+		json.NewEncoder(os.Stdout).Encode(map[string]string{"error": err.Error()})
+	} else {
+		fmt.Fprintln(os.Stderr, "error:", err.Error())
+	}
+	return code
+}
+
+func (g *globalFlags) usageError(err error) int {
+	fmt.Fprintln(os.Stderr, "error:", err.Error())
+	g.fs.Usage()
+	return EXIT_USAGE
+}
+
+// print writes the result as json in json mode, otherwise the text produced by text
+func (g *globalFlags) print(result any, text func() string) {
+	if *g.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(result)
+		return
+	}
+	fmt.Println(text())
+}
+
+func parseCycle(value string) (int64, error) {
+	cycle, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cycle %q is not a number", value)
+	}
+	if cycle < 0 {
+		return 0, errors.New("cycle can not be negative")
+	}
+	return cycle, nil
+}
+
+func parseAddresses(value string) ([]mavryk.Address, error) {
+	if value == "" {
+		return nil, nil
+	}
+	result := make([]mavryk.Address, 0)
+	for _, item := range strings.Split(value, ",") {
+		addr, err := mavryk.ParseAddress(strings.TrimSpace(item))
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", item, err)
+		}
+		result = append(result, addr)
+	}
+	return result, nil
+}
+
+func runVersion(ctx context.Context, args []string) int {
+	flags := newFlagSet("version", "version [flags]", false)
+	if _, err := flags.parse(args); err != nil {
+		return EXIT_USAGE
+	}
+	flags.print(map[string]string{"version": constants.VERSION, "codename": constants.CODENAME}, func() string {
+		return "protocol-rewards version " + constants.VERSION
+	})
+	return EXIT_OK
+}
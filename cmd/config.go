@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mavryk-network/protocol-rewards/notifications"
+)
+
+type configCheckResult struct {
+	Valid    bool     `json:"valid"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+func runConfig(ctx context.Context, args []string) int {
+	flags := newFlagSet("config", "config check [flags]", false)
+	positional, err := flags.parse(args)
+	if err != nil {
+		return EXIT_USAGE
+	}
+	if len(positional) != 1 || positional[0] != "check" {
+		return flags.usageError(errors.New("expected 'check'"))
+	}
+
+	config, err := flags.loadConfiguration()
+	if err != nil {
+		return flags.fail(EXIT_CONFIG, err)
+	}
+
+	result := configCheckResult{
+		Errors:   make([]string, 0),
+		Warnings: make([]string, 0),
+	}
+	if err := config.Validate(); err != nil {
+		result.Errors = strings.Split(err.Error(), "\n")
+	}
+	if err := notifications.ValidateDiscordConfiguration(&config.DiscordNotificator); err != nil {
+		result.Warnings = append(result.Warnings, strings.ReplaceAll(err.Error(), "\n", ": "))
+	}
+	result.Valid = len(result.Errors) == 0
+
+	flags.print(result, func() string {
+		lines := make([]string, 0)
+		for _, e := range result.Errors {
+			lines = append(lines, "error: "+e)
+		}
+		for _, w := range result.Warnings {
+			lines = append(lines, "warning: "+w)
+		}
+		lines = append(lines, fmt.Sprintf("configuration %s is valid: %t", *flags.configPath, result.Valid))
+		return strings.Join(lines, "\n")
+	})
+	if !result.Valid {
+		return EXIT_CONFIG
+	}
+	return EXIT_OK
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/core"
+)
+
+func formatExplanation(explanation *core.DelegationStateExplanation) string {
+	lines := []string{
+		fmt.Sprintf("delegate %s cycle %d (last block %d)", explanation.Delegate.String(), explanation.Cycle, explanation.LastBlockLevel),
+		fmt.Sprintf("minimum at block %d, target amount %d", explanation.BlockWithMinimum, explanation.TargetAmount),
+		fmt.Sprintf("initial delegated balance %d from %d balances at block %d", explanation.InitialDelegatedBalance, len(explanation.InitialBalances), explanation.BlockWithMinimum-1),
+	}
+
+	for i, update := range explanation.Updates {
+		line := fmt.Sprintf("%4d %-28s %-36s %-10s %-20s %14d", i, update.Source, update.Address.String(), update.Kind, update.Category, update.Amount)
+		if update.Deferred != "" {
+			line += fmt.Sprintf(" [deferred: %s]", update.Deferred)
+		}
+		if update.Applied {
+			line += fmt.Sprintf(" -> delegated %d (diff %d)", update.DelegatedBalance, update.Diff)
+		} else {
+			line += " skipped: " + update.SkipReason
+		}
+		if explanation.MatchedUpdateIndex != nil && *explanation.MatchedUpdateIndex == i {
+			line += " <- matched"
+		}
+		lines = append(lines, line)
+	}
+
+	switch {
+	case explanation.Error != "":
+		lines = append(lines, "result: "+explanation.Error)
+	case explanation.MatchedUpdateIndex == nil:
+		lines = append(lines, "result: matched at the beginning of the block")
+	default:
+		lines = append(lines, fmt.Sprintf("result: matched at update %d (%s)", *explanation.MatchedUpdateIndex, explanation.CreatedAt.Kind))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func runExplain(ctx context.Context, args []string) int {
+	flags := newFlagSet("explain", "explain <address> <cycle> [flags]", true)
+	positional, err := flags.parse(args)
+	if err != nil {
+		return EXIT_USAGE
+	}
+	if len(positional) != 2 {
+		return flags.usageError(errors.New("expected '<address> <cycle>'"))
+	}
+	address, err := mavryk.ParseAddress(positional[0])
+	if err != nil {
+		return flags.usageError(err)
+	}
+	cycle, err := parseCycle(positional[1])
+	if err != nil {
+		return flags.usageError(err)
+	}
+
+	engine, code := newEngine(ctx, flags)
+	if engine == nil {
+		return code
+	}
+
+	explanation, err := engine.ExplainDelegationState(ctx, address, cycle, 0)
+	if err != nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+	flags.print(explanation, func() string {
+		return formatExplanation(explanation)
+	})
+	if !explanation.Matched {
+		return EXIT_FAILURE
+	}
+	return EXIT_OK
+}
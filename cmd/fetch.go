@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/core"
+)
+
+func newEngine(ctx context.Context, flags *globalFlags) (*core.Engine, int) {
+	config, err := flags.loadConfiguration()
+	if err != nil {
+		return nil, flags.fail(EXIT_CONFIG, err)
+	}
+
+	options, err := flags.engineOptions(core.TestEngineOptions)
+	if err != nil {
+		return nil, flags.fail(EXIT_FAILURE, err)
+	}
+
+	engine, err := core.NewEngine(ctx, config, options)
+	if err != nil {
+		return nil, flags.fail(EXIT_FAILURE, err)
+	}
+	return engine, EXIT_OK
+}
+
+func runFetch(ctx context.Context, args []string) int {
+	usageLine := "fetch cycle <cycle> | fetch delegate <address> <cycle> [flags]"
+	flags := newFlagSet("fetch", usageLine, true)
+	force := flags.fs.Bool("force", false, "fetch even if the delegation state is already stored")
+	debug := flags.fs.Bool("debug", false, "panic when the minimum delegated balance can not be matched")
+	positional, err := flags.parse(args)
+	if err != nil {
+		return EXIT_USAGE
+	}
+
+	options := &core.FetchOptions{Force: *force || *debug, Debug: *debug}
+
+	switch {
+	case len(positional) == 2 && positional[0] == "cycle":
+		cycle, err := parseCycle(positional[1])
+		if err != nil {
+			return flags.usageError(err)
+		}
+
+		engine, code := newEngine(ctx, flags)
+		if engine == nil {
+			return code
+		}
+
+		result, err := engine.FetchCycleDelegationStates(ctx, cycle, 0, options)
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		flags.print(result, func() string {
+			lines := []string{fmt.Sprintf("cycle %d: fetched %d delegates, %d failed", result.Cycle, result.Delegates-len(result.Failures), len(result.Failures))}
+			for _, failure := range result.Failures {
+				lines = append(lines, fmt.Sprintf("  %s: %s", failure.Delegate.String(), failure.Error))
+			}
+			return strings.Join(lines, "\n")
+		})
+		if len(result.Failures) > 0 {
+			return EXIT_FAILURE
+		}
+		return EXIT_OK
+	case len(positional) == 3 && positional[0] == "delegate":
+		address, err := mavryk.ParseAddress(positional[1])
+		if err != nil {
+			return flags.usageError(err)
+		}
+		cycle, err := parseCycle(positional[2])
+		if err != nil {
+			return flags.usageError(err)
+		}
+
+		engine, code := newEngine(ctx, flags)
+		if engine == nil {
+			return code
+		}
+
+		if err := engine.FetchDelegateDelegationState(ctx, address, cycle, 0, options); err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		flags.print(map[string]any{"cycle": cycle, "delegate": address.String()}, func() string {
+			return fmt.Sprintf("cycle %d: fetched delegate %s", cycle, address.String())
+		})
+		return EXIT_OK
+	default:
+		return flags.usageError(errors.New("expected 'cycle <cycle>' or 'delegate <address> <cycle>'"))
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+type createdApiKey struct {
+	*store.ApiKey
+	Key string `json:"key"`
+}
+
+func runKeys(ctx context.Context, args []string) int {
+	usageLine := "keys create -name <name> | keys list | keys revoke <id> [flags]"
+	flags := newFlagSet("keys", usageLine, false)
+	name := flags.fs.String("name", "", "name of the key owner")
+	rateLimitMax := flags.fs.Int("rate-limit-max", 0, "requests per rate limit window, the configured default if 0")
+	rateLimitExpiration := flags.fs.Int("rate-limit-expiration", 0, "rate limit window in seconds, the configured default if 0")
+	dailyQuota := flags.fs.Int64("daily-quota", 0, "requests per UTC day, unlimited if 0")
+	positional, err := flags.parse(args)
+	if err != nil {
+		return EXIT_USAGE
+	}
+	if len(positional) == 0 {
+		return flags.usageError(errors.New("subcommand is required"))
+	}
+
+	switch {
+	case positional[0] == "create" && len(positional) == 1:
+		if strings.TrimSpace(*name) == "" {
+			return flags.usageError(errors.New("-name is required"))
+		}
+		if *rateLimitMax < 0 || *rateLimitExpiration < 0 || *dailyQuota < 0 {
+			return flags.usageError(errors.New("limits can not be negative"))
+		}
+		s, code := newStore(flags)
+		if s == nil {
+			return code
+		}
+		apiKey, key, err := s.CreateApiKey(strings.TrimSpace(*name), *rateLimitMax, *rateLimitExpiration, *dailyQuota)
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		flags.print(createdApiKey{ApiKey: apiKey, Key: key}, func() string {
+			return fmt.Sprintf("created api key %d for %s, it is not shown again:\n%s", apiKey.Id, apiKey.Name, key)
+		})
+	case positional[0] == "list" && len(positional) == 1:
+		s, code := newStore(flags)
+		if s == nil {
+			return code
+		}
+		keys, err := s.ListApiKeys()
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		flags.print(keys, func() string {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "%-6s %-20s %-12s %-12s %-12s %-12s %s", "ID", "NAME", "PREFIX", "RATE LIMIT", "QUOTA", "TODAY", "STATUS")
+			for _, key := range keys {
+				status := "active"
+				if key.RevokedAt != nil {
+					status = "revoked " + key.RevokedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(&builder, "\n%-6d %-20s %-12s %-12s %-12d %-12d %s", key.Id, key.Name, key.Prefix,
+					fmt.Sprintf("%d/%ds", key.RateLimitMax, key.RateLimitExpiration), key.DailyQuota, key.RequestsToday, status)
+			}
+			return builder.String()
+		})
+	case positional[0] == "revoke" && len(positional) == 2:
+		id, err := strconv.ParseInt(positional[1], 10, 64)
+		if err != nil {
+			return flags.usageError(err)
+		}
+		s, code := newStore(flags)
+		if s == nil {
+			return code
+		}
+		if err := s.RevokeApiKey(id); err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		flags.print(map[string]int64{"revoked": id}, func() string {
+			return fmt.Sprintf("revoked api key %d", id)
+		})
+	default:
+		return flags.usageError(fmt.Errorf("unknown subcommand %q", strings.Join(positional, " ")))
+	}
+	return EXIT_OK
+}
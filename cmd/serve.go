@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/mavryk-network/protocol-rewards/api"
+	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/grpcapi"
+)
+
+func runServe(ctx context.Context, args []string) int {
+	flags := newFlagSet("serve", "serve [flags]", true)
+	if _, err := flags.parse(args); err != nil {
+		return EXIT_USAGE
+	}
+
+	config, err := flags.loadConfiguration()
+	if err != nil {
+		return flags.fail(EXIT_CONFIG, err)
+	}
+	if err := config.Validate(); err != nil {
+		return flags.fail(EXIT_CONFIG, err)
+	}
+
+	options, err := flags.engineOptions(core.DefaultEngineOptions)
+	if err != nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+
+	engine, err := core.NewEngine(ctx, config, options)
+	if err != nil {
+		slog.Error("failed to create engine", "error", err.Error())
+		return EXIT_FAILURE
+	}
+
+	publicApiApp, err := api.CreatePublicApi(config, engine)
+	if err != nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+	defer publicApiApp.Shutdown()
+
+	privateApiApp, err := api.CreatePrivateApi(config, engine)
+	if err != nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+	if privateApiApp != nil {
+		defer privateApiApp.Shutdown()
+	}
+
+	grpcServer, err := grpcapi.CreateGrpcApi(config, engine)
+	if err != nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+	if grpcServer != nil {
+		defer grpcServer.GracefulStop()
+	}
+
+	go configuration.Watch(ctx, *flags.configPath, config, func(config *configuration.Runtime) {
+		flags.applyLogLevel(config)
+
+		if err := engine.Reload(ctx, config); err != nil {
+			slog.Error("failed to reload engine", "error", err.Error())
+		}
+		api.ReloadPublicApi(config, engine)
+	})
+
+	<-ctx.Done()
+	return EXIT_OK
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/signing"
+)
+
+// readSignedResponse fetches the url and builds the envelope from the signature headers,
+// a body which is an envelope itself (?envelope=true) is used as is
+func readSignedResponse(ctx context.Context, url string) (*signing.Envelope, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", response.StatusCode, string(body))
+	}
+	if response.Header.Get(signing.HeaderSignature) == "" {
+		return readEnvelope(body)
+	}
+	return signing.EnvelopeFromResponse(response.Header, body)
+}
+
+// subjectFromURL is the subject a response of the signed endpoints has to be signed for, nil for other urls
+func subjectFromURL(rawURL string) *signing.Subject {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	var address, cycle string
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	switch {
+	case len(parts) >= 3 && parts[len(parts)-3] == "delegate":
+		cycle, address = parts[len(parts)-2], parts[len(parts)-1]
+	case len(parts) >= 5 && strings.Join(parts[len(parts)-5:len(parts)-2], "/") == "v1/rewards/split":
+		address, cycle = parts[len(parts)-2], parts[len(parts)-1]
+	default:
+		return nil
+	}
+	delegate, err := mavryk.ParseAddress(address)
+	if err != nil {
+		return nil
+	}
+	parsedCycle, err := strconv.ParseInt(cycle, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return signing.NewSubject(delegate, parsedCycle)
+}
+
+func readEnvelope(data []byte) (*signing.Envelope, error) {
+	var envelope signing.Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Signature == "" {
+		return nil, errors.New("no signature found")
+	}
+	return &envelope, nil
+}
+
+func runSignature(ctx context.Context, args []string) int {
+	usageLine := "signature verify <url | envelope file> -signer <address> | signature address [flags]"
+	flags := newFlagSet("signature", usageLine, false)
+	signerFlag := flags.fs.String("signer", "", "pinned address of the expected signer")
+	positional, err := flags.parse(args)
+	if err != nil {
+		return EXIT_USAGE
+	}
+
+	switch {
+	case len(positional) == 2 && positional[0] == "verify":
+		signer, err := mavryk.ParseAddress(*signerFlag)
+		if err != nil {
+			return flags.usageError(errors.New("-signer has to be a valid address"))
+		}
+
+		var envelope *signing.Envelope
+		// responses fetched here have to be signed for the requested delegate and cycle
+		var subject *signing.Subject
+		source := positional[1]
+		if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+			subject = subjectFromURL(source)
+			envelope, err = readSignedResponse(ctx, source)
+		} else {
+			var data []byte
+			if data, err = os.ReadFile(source); err == nil {
+				envelope, err = readEnvelope(data)
+			}
+		}
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+
+		if err := envelope.Verify(signer, subject); err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		result := map[string]string{"status": "valid", "signer": signer.String()}
+		if envelope.Subject != nil {
+			result["subject"] = envelope.Subject.String()
+		}
+		flags.print(result, func() string {
+			if envelope.Subject != nil {
+				return fmt.Sprintf("valid signature by %s for %s", signer.String(), envelope.Subject.String())
+			}
+			return fmt.Sprintf("valid signature by %s", signer.String())
+		})
+		return EXIT_OK
+	case len(positional) == 1 && positional[0] == "address":
+		config, err := flags.loadConfiguration()
+		if err != nil {
+			return flags.fail(EXIT_CONFIG, err)
+		}
+		if config.SigningKey == "" {
+			return flags.fail(EXIT_CONFIG, errors.New("no signing key configured"))
+		}
+		signer, err := signing.NewSigner(config.SigningKey)
+		if err != nil {
+			return flags.fail(EXIT_CONFIG, err)
+		}
+		flags.print(map[string]string{"signer": signer.Address().String()}, func() string {
+			return signer.Address().String()
+		})
+		return EXIT_OK
+	default:
+		return flags.usageError(errors.New("expected 'verify <url | file>' or 'address'"))
+	}
+}
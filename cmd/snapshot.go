@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+func runSnapshot(ctx context.Context, args []string) int {
+	usageLine := "snapshot export -from <cycle> -to <cycle> -o <file> | snapshot import <file> [flags]"
+	flags := newFlagSet("snapshot", usageLine, true)
+	from := flags.fs.Int64("from", -1, "first cycle to export")
+	to := flags.fs.Int64("to", -1, "last cycle to export, defaults to -from")
+	output := flags.fs.String("o", "", "snapshot file to create")
+	overwrite := flags.fs.Bool("overwrite", false, "replace already stored delegation states on import")
+	positional, err := flags.parse(args)
+	if err != nil {
+		return EXIT_USAGE
+	}
+
+	switch {
+	case len(positional) == 1 && positional[0] == "export":
+		if *to < 0 {
+			*to = *from
+		}
+		if *from < 0 || *to < *from {
+			return flags.usageError(errors.New("-from is required and has to be less or equal to -to"))
+		}
+		if *output == "" {
+			return flags.usageError(errors.New("-o is required"))
+		}
+
+		engine, code := newEngine(ctx, flags)
+		if engine == nil {
+			return code
+		}
+
+		file, err := os.Create(*output)
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		defer file.Close()
+
+		writer := bufio.NewWriter(file)
+		manifest, err := engine.CreateSnapshot(*from, *to, writer)
+		if err == nil {
+			err = writer.Flush()
+		}
+		if err != nil {
+			os.Remove(*output)
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		flags.print(manifest, func() string {
+			states := 0
+			for _, cycle := range manifest.Cycles {
+				states += cycle.States
+			}
+			return fmt.Sprintf("created snapshot %s of chain %s: %d cycles, %d delegation states", *output, manifest.ChainId, len(manifest.Cycles), states)
+		})
+		return EXIT_OK
+	case len(positional) == 2 && positional[0] == "import":
+		file, err := os.Open(positional[1])
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		defer file.Close()
+
+		engine, code := newEngine(ctx, flags)
+		if engine == nil {
+			return code
+		}
+
+		result, err := engine.ImportSnapshot(bufio.NewReader(file), *overwrite)
+		if err != nil {
+			if result != nil && len(result.Cycles) > 0 {
+				slog.Warn("snapshot import failed, cycles merged before stay imported", "cycles", result.Cycles)
+			}
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		flags.print(result, func() string {
+			return fmt.Sprintf("imported snapshot of chain %s cycles %d - %d: %d delegation states imported, %d already stored", result.Manifest.ChainId, result.Manifest.FromCycle, result.Manifest.ToCycle, result.Imported, result.Skipped)
+		})
+		return EXIT_OK
+	default:
+		return flags.usageError(errors.New("expected 'export' or 'import <file>'"))
+	}
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mavryk-network/protocol-rewards/export"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+func newStore(flags *globalFlags) (*store.Store, int) {
+	config, err := flags.loadConfiguration()
+	if err != nil {
+		return nil, flags.fail(EXIT_CONFIG, err)
+	}
+
+	s, err := store.NewStore(config)
+	if err != nil {
+		return nil, flags.fail(EXIT_FAILURE, err)
+	}
+	return s, EXIT_OK
+}
+
+func runExport(ctx context.Context, args []string) int {
+	flags := newFlagSet("export", "export -from <cycle> -to <cycle> [flags]", false)
+	from := flags.fs.Int64("from", -1, "first cycle to export")
+	to := flags.fs.Int64("to", -1, "last cycle to export, defaults to -from")
+	delegates := flags.fs.String("delegates", "", "comma separated list of delegates to export, all if empty")
+	formatFlag := flags.fs.String("format", string(export.FormatJSONL), "output format (csv, jsonl, parquet)")
+	output := flags.fs.String("o", "", "output file, stdout if empty")
+	if _, err := flags.parse(args); err != nil {
+		return EXIT_USAGE
+	}
+	format, err := export.ParseFormat(*formatFlag)
+	if err != nil {
+		return flags.usageError(err)
+	}
+	if *to < 0 {
+		*to = *from
+	}
+	if *from < 0 || *to < *from {
+		return flags.usageError(errors.New("-from is required and has to be less or equal to -to"))
+	}
+	delegateFilter, err := parseAddresses(*delegates)
+	if err != nil {
+		return flags.usageError(err)
+	}
+
+	s, code := newStore(flags)
+	if s == nil {
+		return code
+	}
+
+	var writer io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	buffered := bufio.NewWriter(writer)
+	count, err := export.Export(ctx, s, &export.Filter{FromCycle: *from, ToCycle: *to, Delegates: delegateFilter}, format, buffered)
+	if err != nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+	slog.Info("exported delegation states", "from", *from, "to", *to, "format", format, "rows", count)
+	return EXIT_OK
+}
+
+func runPrune(ctx context.Context, args []string) int {
+	flags := newFlagSet("prune", "prune [-cycle <cycle>] [flags]", true)
+	cycle := flags.fs.Int64("cycle", 0, "cycle to apply the retention policy relative to, defaults to the last fetched cycle")
+	if _, err := flags.parse(args); err != nil {
+		return EXIT_USAGE
+	}
+
+	engine, code := newEngine(ctx, flags)
+	if engine == nil {
+		return code
+	}
+
+	if *cycle == 0 {
+		lastFetchedCycle, err := engine.GetLastFetchedCycle()
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		*cycle = lastFetchedCycle
+	}
+
+	report, err := engine.ApplyRetention(*cycle)
+	if err != nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+	flags.print(report, func() string {
+		return fmt.Sprintf("applied retention relative to cycle %d: pruned cycles %v, archived cycles %v", report.Cycle, report.Pruned, report.Archived)
+	})
+	return EXIT_OK
+}
+
+func runMigrate(ctx context.Context, args []string) int {
+	flags := newFlagSet("migrate", "migrate [status | up | down] [flags]", false)
+	target := flags.fs.Int("to", -1, "target schema version, defaults to the latest version for up and the previous version for down")
+	positional, err := flags.parse(args)
+	if err != nil {
+		return EXIT_USAGE
+	}
+	action := "up"
+	if len(positional) > 0 {
+		action = positional[0]
+	}
+	if len(positional) > 1 || (action != "status" && action != "up" && action != "down") {
+		return flags.usageError(errors.New("expected 'status', 'up' or 'down'"))
+	}
+
+	config, err := flags.loadConfiguration()
+	if err != nil {
+		return flags.fail(EXIT_CONFIG, err)
+	}
+	// the schema is managed explicitly here, open without migrating
+	s, err := store.OpenStore(config)
+	if err != nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+
+	if action == "status" {
+		status, err := s.MigrationStatus()
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		flags.print(status, func() string {
+			lines := []string{fmt.Sprintf("binary schema version %d", store.LatestSchemaVersion())}
+			for _, migration := range status {
+				applied := "pending"
+				if migration.AppliedAt != nil {
+					applied = "applied " + migration.AppliedAt.Format(time.RFC3339)
+				}
+				lines = append(lines, fmt.Sprintf("  %4d %-40s %s", migration.Version, migration.Name, applied))
+			}
+			return strings.Join(lines, "\n")
+		})
+		return EXIT_OK
+	}
+
+	var versions []int
+	switch action {
+	case "up":
+		if *target < 0 {
+			*target = 0
+		}
+		versions, err = s.MigrateUp(*target)
+	case "down":
+		if *target < 0 {
+			current, err := s.SchemaVersion()
+			if err != nil {
+				return flags.fail(EXIT_FAILURE, err)
+			}
+			*target = max(current-1, 0)
+		}
+		versions, err = s.MigrateDown(*target)
+	}
+	if err != nil {
+		return flags.fail(EXIT_FAILURE, err)
+	}
+	flags.print(map[string]any{"action": action, "versions": versions}, func() string {
+		if len(versions) == 0 {
+			return "nothing to migrate"
+		}
+		return fmt.Sprintf("migrated %s: %v", action, versions)
+	})
+	return EXIT_OK
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/core"
+)
+
+func formatVerificationResult(result *core.VerificationResult) string {
+	switch {
+	case result.Error != "":
+		return fmt.Sprintf("cycle %d delegate %s: failed to verify: %s", result.Cycle, result.Delegate.String(), result.Error)
+	case !result.Stored:
+		return fmt.Sprintf("cycle %d delegate %s: not stored", result.Cycle, result.Delegate.String())
+	case result.IsValid():
+		return fmt.Sprintf("cycle %d delegate %s: ok", result.Cycle, result.Delegate.String())
+	}
+
+	lines := []string{fmt.Sprintf("cycle %d delegate %s: %d mismatches (stored status %d, computed status %d)", result.Cycle, result.Delegate.String(), len(result.Mismatches), result.StoredStatus, result.ComputedStatus)}
+	for _, mismatch := range result.Mismatches {
+		lines = append(lines, fmt.Sprintf("  %s: stored %+v computed %+v", mismatch.Address.String(), mismatch.Stored, mismatch.Computed))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func runVerify(ctx context.Context, args []string) int {
+	usageLine := "verify cycle <cycle> | verify delegate <address> <cycle> [flags]"
+	flags := newFlagSet("verify", usageLine, true)
+	positional, err := flags.parse(args)
+	if err != nil {
+		return EXIT_USAGE
+	}
+
+	var results []*core.VerificationResult
+	switch {
+	case len(positional) == 2 && positional[0] == "cycle":
+		cycle, err := parseCycle(positional[1])
+		if err != nil {
+			return flags.usageError(err)
+		}
+
+		engine, code := newEngine(ctx, flags)
+		if engine == nil {
+			return code
+		}
+
+		results, err = engine.VerifyCycleDelegationStates(ctx, cycle, 0)
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+	case len(positional) == 3 && positional[0] == "delegate":
+		address, err := mavryk.ParseAddress(positional[1])
+		if err != nil {
+			return flags.usageError(err)
+		}
+		cycle, err := parseCycle(positional[2])
+		if err != nil {
+			return flags.usageError(err)
+		}
+
+		engine, code := newEngine(ctx, flags)
+		if engine == nil {
+			return code
+		}
+
+		result, err := engine.VerifyDelegateDelegationState(ctx, address, cycle, 0)
+		if err != nil {
+			return flags.fail(EXIT_FAILURE, err)
+		}
+		results = []*core.VerificationResult{result}
+	default:
+		return flags.usageError(errors.New("expected 'cycle <cycle>' or 'delegate <address> <cycle>'"))
+	}
+
+	valid := true
+	flags.print(results, func() string {
+		lines := make([]string, 0, len(results))
+		for _, result := range results {
+			lines = append(lines, formatVerificationResult(result))
+		}
+		return strings.Join(lines, "\n")
+	})
+	for _, result := range results {
+		valid = valid && result.IsValid()
+	}
+	if !valid {
+		return EXIT_FAILURE
+	}
+	return EXIT_OK
+}
@@ -140,9 +140,18 @@ func NewDelegationState(delegate *rpc.Delegate, cycle int64, lastBlockLevel rpc.
 }
 
 func (d *DelegationState) overstakeFactor() mavryk.Z {
-	bakerStakingBalance := d.GetBakerStakedBalance()
-	limit := mavryk.NewZ(d.Parameters.LimitOfStakingOverBakingMillionth).Mul64(bakerStakingBalance).Div64(1_000_000)
-	stakedBalance := mavryk.NewZ(d.GetStakersStakedBalance())
+	return OverstakeFactor(d.Parameters.LimitOfStakingOverBakingMillionth, d.GetBakerStakedBalance(), d.GetStakersStakedBalance())
+}
+
+// StakingLimit is the external stake the baker accepts before it is overstaked
+func StakingLimit(limitOfStakingOverBakingMillionth, bakerStakedBalance int64) mavryk.Z {
+	return mavryk.NewZ(limitOfStakingOverBakingMillionth).Mul64(bakerStakedBalance).Div64(1_000_000)
+}
+
+// OverstakeFactor is the portion of stakers staked balance over the limit in OVERSTAKE_PRECISION
+func OverstakeFactor(limitOfStakingOverBakingMillionth, bakerStakedBalance, stakersStakedBalance int64) mavryk.Z {
+	limit := StakingLimit(limitOfStakingOverBakingMillionth, bakerStakedBalance)
+	stakedBalance := mavryk.NewZ(stakersStakedBalance)
 	if stakedBalance.IsLess(limit) {
 		return mavryk.Zero
 	}
@@ -212,6 +221,17 @@ func (d *DelegationState) GetDelegatorBalanceInfos() DelegationStateBalances {
 	return result
 }
 
+// returns copy of all tracked balances including the baker and delegators which left
+func (d *DelegationState) GetBalanceInfos() DelegationStateBalances {
+	d.balancesMtx.RLock()
+	defer d.balancesMtx.RUnlock()
+	result := make(DelegationStateBalances, len(d.balances))
+	for addr, balanceInfo := range d.balances {
+		result[addr] = balanceInfo
+	}
+	return result
+}
+
 func (d *DelegationState) GetDelegatedBalance() int64 {
 	return lo.Reduce(lo.Values(d.GetDelegatorAndBakerBalances()), func(acc int64, balance DelegatorBalances, _ int) int64 {
 		return acc + balance.DelegatedBalance
@@ -298,8 +318,13 @@ func (d *DelegationState) GetBakingPower() int64 {
 		return acc + balance.DelegatedBalance
 	}, 0)
 
-	if d.Cycle < 748 {
-		return stakedPower + delegatedPower
+	return BakingPower(d.Cycle, stakedPower, delegatedPower)
+}
+
+// BakingPower applies the protocol weight of delegated balance in the cycle
+func BakingPower(cycle, staked, delegated int64) int64 {
+	if cycle < constants.DELEGATED_BAKING_POWER_HALVED_FROM_CYCLE {
+		return staked + delegated
 	}
-	return stakedPower + delegatedPower/2
+	return staked + delegated/2
 }
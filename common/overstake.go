@@ -0,0 +1,80 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+)
+
+// OverstakeAnalytics describes how close the baker is to its staking limit, balances are in mumav.
+// Spare capacity and own stake to absorb are additionally given in mav.
+type OverstakeAnalytics struct {
+	Baker             mavryk.Address    `json:"baker"`
+	Cycle             int64             `json:"cycle"`
+	StakingParameters StakingParameters `json:"staking_parameters"`
+	OwnStakedMumav    int64             `json:"own_staked_mumav"`
+	// external stake accepted before stakers are overstaked
+	StakingLimitMumav   int64 `json:"staking_limit_mumav"`
+	ExternalStakedMumav int64 `json:"external_staked_mumav"`
+	// external stake over the limit, counted as delegated
+	OverstakedMumav          int64 `json:"overstaked_mumav"`
+	OverstakeFactorMillionth int64 `json:"overstake_factor_millionth"`
+	SpareCapacityMumav       int64 `json:"spare_capacity_mumav"`
+	// decimal string, exact unlike a float
+	SpareCapacityMav string `json:"spare_capacity_mav"`
+	// additional own stake needed to bring the overstake to zero, nil if the baker accepts no external stake
+	OwnStakeToAbsorbMumav *int64  `json:"own_stake_to_absorb_mumav"`
+	OwnStakeToAbsorbMav   *string `json:"own_stake_to_absorb_mav"`
+}
+
+// FormatMav formats a mumav amount as a decimal mav string with all 6 decimals
+func FormatMav(mumav int64) string {
+	sign := ""
+	if mumav < 0 {
+		sign = "-"
+	}
+	whole, fraction := mumav/constants.MUMAV_PER_MAV, mumav%constants.MUMAV_PER_MAV
+	return fmt.Sprintf("%s%d.%06d", sign, max(whole, -whole), max(fraction, -fraction))
+}
+
+// AnalyzeOverstake evaluates the staking limit of the baker against the stored balances
+func AnalyzeOverstake(baker mavryk.Address, cycle int64, parameters StakingParameters, balances DelegatedBalances) *OverstakeAnalytics {
+	result := &OverstakeAnalytics{
+		Baker:             baker,
+		Cycle:             cycle,
+		StakingParameters: parameters,
+	}
+	for addr, balance := range balances {
+		if addr.Equal(baker) {
+			result.OwnStakedMumav += balance.StakedBalance
+			continue
+		}
+		result.ExternalStakedMumav += balance.StakedBalance
+		result.OverstakedMumav += balance.OverstakedBalance
+	}
+
+	limitMillionth := parameters.LimitOfStakingOverBakingMillionth
+	result.StakingLimitMumav = StakingLimit(limitMillionth, result.OwnStakedMumav).Int64()
+	if result.ExternalStakedMumav > 0 {
+		result.OverstakeFactorMillionth = OverstakeFactor(limitMillionth, result.OwnStakedMumav, result.ExternalStakedMumav).
+			Mul64(1_000_000).Div64(OVERSTAKE_PRECISION).Int64()
+	}
+	result.SpareCapacityMumav = max(result.StakingLimitMumav-result.ExternalStakedMumav, 0)
+	result.SpareCapacityMav = FormatMav(result.SpareCapacityMumav)
+
+	var toAbsorb int64
+	switch {
+	case result.ExternalStakedMumav <= result.StakingLimitMumav:
+	case limitMillionth <= 0:
+		return result
+	default:
+		// smallest own stake with a limit covering the external stake
+		needed := mavryk.NewZ(result.ExternalStakedMumav).Mul64(1_000_000).Add64(limitMillionth - 1).Div64(limitMillionth)
+		toAbsorb = needed.Int64() - result.OwnStakedMumav
+	}
+	toAbsorbMav := FormatMav(toAbsorb)
+	result.OwnStakeToAbsorbMumav = &toAbsorb
+	result.OwnStakeToAbsorbMav = &toAbsorbMav
+	return result
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeOverstake(t *testing.T) {
+	assert := assert.New(t)
+
+	baker := mavryk.MustParseAddress("mv1ELYevTeKz1tb8J8cqtYnz2vRdv9tamNmr")
+	delegator := mavryk.MustParseAddress("mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL")
+	parameters := StakingParameters{LimitOfStakingOverBakingMillionth: 2_000_000}
+
+	result := AnalyzeOverstake(baker, 750, parameters, DelegatedBalances{
+		baker:     {StakedBalance: 1000},
+		delegator: {StakedBalance: 3000, OverstakedBalance: 999},
+	})
+	assert.Equal(int64(2000), result.StakingLimitMumav)
+	assert.Equal(int64(3000), result.ExternalStakedMumav)
+	assert.Equal(int64(999), result.OverstakedMumav)
+	assert.Equal(int64(333333), result.OverstakeFactorMillionth)
+	assert.Equal(int64(0), result.SpareCapacityMumav)
+	assert.Equal(int64(500), *result.OwnStakeToAbsorbMumav)
+
+	result = AnalyzeOverstake(baker, 750, parameters, DelegatedBalances{
+		baker:     {StakedBalance: 1000},
+		delegator: {StakedBalance: 1500},
+	})
+	assert.Equal(int64(0), result.OverstakeFactorMillionth)
+	assert.Equal(int64(500), result.SpareCapacityMumav)
+	assert.Equal("0.000500", result.SpareCapacityMav)
+	assert.Equal(int64(0), *result.OwnStakeToAbsorbMumav)
+	assert.Equal("0.000000", *result.OwnStakeToAbsorbMav)
+
+	// external stake is not accepted at all
+	result = AnalyzeOverstake(baker, 750, StakingParameters{}, DelegatedBalances{
+		baker:     {StakedBalance: 1000},
+		delegator: {StakedBalance: 100, OverstakedBalance: 100},
+	})
+	assert.Equal(int64(1_000_000), result.OverstakeFactorMillionth)
+	assert.Nil(result.OwnStakeToAbsorbMumav)
+	assert.Nil(result.OwnStakeToAbsorbMav)
+}
+
+func TestFormatMav(t *testing.T) {
+	assert.Equal(t, "1.500000", FormatMav(1_500_000))
+	assert.Equal(t, "0.000001", FormatMav(1))
+	assert.Equal(t, "-12.000250", FormatMav(-12_000_250))
+}
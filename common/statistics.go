@@ -1,15 +1,75 @@
 package common
 
-import "github.com/mavryk-network/mvgo/mavryk"
+import (
+	"github.com/mavryk-network/mvgo/mavryk"
+)
+
+// DelegatorSizeStatistics describes delegated plus staked balances of delegators
+type DelegatorSizeStatistics struct {
+	Min    int64 `json:"min"`
+	Median int64 `json:"median"`
+	Max    int64 `json:"max"`
+}
 
 type DelegateCycleStatistics struct {
 	ExternalStaked    int64 `json:"external_staked"`
 	OwnStaked         int64 `json:"own_staked"`
 	ExternalDelegated int64 `json:"external_delegated"`
 	OwnDelegated      int64 `json:"own_delegated"`
+	// part of external staked balance counted as delegated
+	ExternalOverstaked int64                   `json:"external_overstaked"`
+	Delegators         int                     `json:"delegators"`
+	Stakers            int                     `json:"stakers"`
+	BakingPower        int64                   `json:"baking_power"`
+	NetworkShare       float64                 `json:"network_share"`
+	DelegatorSizes     DelegatorSizeStatistics `json:"delegator_sizes"`
+	Status             string                  `json:"status,omitempty"`
+}
+
+// NetworkCycleStatistics sums up all bakers of the cycle, delegators and stakers are counted once
+type NetworkCycleStatistics struct {
+	Bakers         int                     `json:"bakers"`
+	Delegators     int                     `json:"delegators"`
+	Stakers        int                     `json:"stakers"`
+	Staked         int64                   `json:"staked"`
+	Delegated      int64                   `json:"delegated"`
+	Overstaked     int64                   `json:"overstaked"`
+	BakingPower    int64                   `json:"baking_power"`
+	DelegatorSizes DelegatorSizeStatistics `json:"delegator_sizes"`
+	Statuses       map[string]int          `json:"statuses"`
 }
 
 type CycleStatistics struct {
 	Cycle     int64                                      `json:"cycle"`
 	Delegates map[mavryk.Address]DelegateCycleStatistics `json:"delegates"`
+	Network   NetworkCycleStatistics                     `json:"network"`
+}
+
+func NewCycleStatistics(cycle int64) *CycleStatistics {
+	return &CycleStatistics{
+		Cycle:     cycle,
+		Delegates: make(map[mavryk.Address]DelegateCycleStatistics),
+		Network:   NetworkCycleStatistics{Statuses: make(map[string]int)},
+	}
+}
+
+// Add adds the baker to the network baker count, baking power and statuses
+func (s *CycleStatistics) Add(baker mavryk.Address, stats DelegateCycleStatistics) {
+	s.Delegates[baker] = stats
+	s.Network.Bakers++
+	s.Network.BakingPower += stats.BakingPower
+	if stats.Status != "" {
+		s.Network.Statuses[stats.Status]++
+	}
+}
+
+// ComputeNetworkShares sets the share of every baker in the network baking power
+func (s *CycleStatistics) ComputeNetworkShares() {
+	if s.Network.BakingPower <= 0 {
+		return
+	}
+	for baker, stats := range s.Delegates {
+		stats.NetworkShare = float64(stats.BakingPower) / float64(s.Network.BakingPower)
+		s.Delegates[baker] = stats
+	}
 }
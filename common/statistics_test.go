@@ -0,0 +1,27 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCycleStatistics(t *testing.T) {
+	assert := assert.New(t)
+
+	baker1 := mavryk.MustParseAddress("mv1ELYevTeKz1tb8J8cqtYnz2vRdv9tamNmr")
+	baker2 := mavryk.MustParseAddress("mv1FpkYtjBvNqp8MbhQCqTyJr3bNXNkBvVfs")
+
+	result := NewCycleStatistics(750)
+	result.Add(baker1, DelegateCycleStatistics{BakingPower: 1530, Status: "ok"})
+	result.Add(baker2, DelegateCycleStatistics{BakingPower: 950, Status: "minimum_not_available"})
+	// pruned cycles have no status
+	result.Add(mavryk.BurnAddress, DelegateCycleStatistics{})
+	result.ComputeNetworkShares()
+
+	assert.Equal(3, result.Network.Bakers)
+	assert.Equal(int64(2480), result.Network.BakingPower)
+	assert.InDelta(950.0/2480.0, result.Delegates[baker2].NetworkShare, 1e-9)
+	assert.Equal(map[string]int{"ok": 1, "minimum_not_available": 1}, result.Network.Statuses)
+}
@@ -0,0 +1,106 @@
+package configuration
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/mavryk-network/protocol-rewards/constants"
+)
+
+// MergeReloadable returns a copy of current with all reloadable fields taken from next.
+// Fields which can not be changed without a restart keep their current value and the change is logged.
+// Values from the environment, like the log level, are only read at start as they are not overridden by .env.
+func MergeReloadable(current, next *Runtime) *Runtime {
+	result := *current
+
+	result.Providers = next.Providers
+	result.MvktProviders = next.MvktProviders
+	result.DiscordNotificator = next.DiscordNotificator
+	result.Delegates = next.Delegates
+	result.RateLimit = next.RateLimit
+
+	if !reflect.DeepEqual(current.Database, next.Database) {
+		slog.Warn("database configuration can not be reloaded, restart required", "field", "database")
+	}
+	if !reflect.DeepEqual(current.Storage, next.Storage) {
+		slog.Warn("storage configuration can not be reloaded, restart required", "field", "storage")
+	}
+	if current.Listen != next.Listen {
+		slog.Warn("listen address can not be reloaded, restart required", "field", constants.LISTEN)
+	}
+	if current.PrivateListen != next.PrivateListen {
+		slog.Warn("private listen address can not be reloaded, restart required", "field", constants.PRIVATE_LISTEN)
+	}
+	if current.ResponseCache != next.ResponseCache {
+		slog.Warn("response cache configuration can not be reloaded, restart required", "field", "response_cache")
+	}
+	if !reflect.DeepEqual(current.PrivateApi, next.PrivateApi) {
+		slog.Warn("private api configuration can not be reloaded, restart required", "field", "private_api")
+	}
+	if current.GrpcListen != next.GrpcListen {
+		slog.Warn("grpc listen address can not be reloaded, restart required", "field", constants.GRPC_LISTEN)
+	}
+	if current.SigningKey != next.SigningKey {
+		slog.Warn("signing key can not be reloaded, restart required", "field", constants.SIGNING_KEY)
+	}
+
+	return &result
+}
+
+// Watch reloads the configuration at path whenever the file changes or the process receives SIGHUP.
+// The merged configuration is passed to apply, invalid configurations are not applied. It blocks until ctx is done.
+func Watch(ctx context.Context, path string, current *Runtime, apply func(config *Runtime)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(constants.CONFIG_WATCH_INTERVAL_SECONDS * time.Second)
+	defer ticker.Stop()
+
+	lastModified := getModificationTime(path)
+	reload := func(reason string) {
+		next, err := LoadConfiguration(path)
+		if err != nil {
+			slog.Error("failed to reload configuration, keeping the current one", "reason", reason, "error", err.Error())
+			return
+		}
+		if err := next.Validate(); err != nil {
+			slog.Error("reloaded configuration is invalid, keeping the current one", "reason", reason, "error", err.Error())
+			return
+		}
+
+		slog.Info("reloading configuration", "reason", reason)
+		current = MergeReloadable(current, next)
+		apply(current)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			lastModified = getModificationTime(path)
+			reload("sighup")
+		case <-ticker.C:
+			modified := getModificationTime(path)
+			if modified.Equal(lastModified) {
+				continue
+			}
+			lastModified = modified
+			reload("file changed")
+		}
+	}
+}
+
+func getModificationTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
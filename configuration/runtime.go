@@ -1,6 +1,8 @@
 package configuration
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 
@@ -9,6 +11,7 @@ import (
 	"github.com/mavryk-network/mvgo/mavryk"
 	"github.com/mavryk-network/protocol-rewards/constants"
 	"github.com/mavryk-network/protocol-rewards/notifications"
+	"github.com/mavryk-network/protocol-rewards/signing"
 )
 
 type DatabaseConfiguration struct {
@@ -24,9 +27,47 @@ func (dc *DatabaseConfiguration) Unwrap() (host string, port string, user string
 }
 
 type StorageConfiguration struct {
-	// current supported modes are [rolling] and [archive]
-	Mode         constants.StorageKind `json:"mode"`
-	StoredCycles int                   `json:"stored_cycles"`
+	// deprecated, [rolling] with stored_cycles is mapped to retention.detailed_cycles, [archive] keeps everything
+	Mode         constants.StorageKind  `json:"mode"`
+	StoredCycles int                    `json:"stored_cycles"`
+	Retention    RetentionConfiguration `json:"retention"`
+}
+
+type RetentionConfiguration struct {
+	// number of most recent cycles kept with per delegator balances, 0 keeps all cycles in full detail
+	DetailedCycles int `json:"detailed_cycles"`
+	// keep per baker aggregates of cycles pruned from detail
+	KeepAggregates bool `json:"keep_aggregates"`
+	// if set pruned cycles are written into compressed archive files in the directory before deletion
+	ArchiveDirectory string `json:"archive_directory"`
+}
+
+type RateLimitConfiguration struct {
+	Max        int `json:"max"`
+	Expiration int `json:"expiration"` // seconds
+	// keeps the rate limit windows in the database so replicas enforce a common limit
+	Shared bool `json:"shared"`
+}
+
+type ResponseCacheConfiguration struct {
+	// number of responses kept in memory, 0 disables the cache
+	Size int `json:"size"`
+}
+
+type PrivateApiClient struct {
+	Name string                   `json:"name"`
+	Role constants.PrivateApiRole `json:"role"`
+	// bearer token, optional with mutual tls where the certificate common name has to match the name
+	Token string `json:"token"`
+}
+
+type PrivateApiConfiguration struct {
+	// required if the private api listens, without clients every request is rejected
+	Clients        []PrivateApiClient `json:"clients"`
+	TlsCertificate string             `json:"tls_certificate"`
+	TlsKey         string             `json:"tls_key"`
+	// requires client certificates signed by the ca
+	TlsClientCa string `json:"tls_client_ca"`
 }
 
 type Runtime struct {
@@ -36,9 +77,15 @@ type Runtime struct {
 	Storage            StorageConfiguration                          `json:"storage"`
 	DiscordNotificator notifications.DiscordNotificatorConfiguration `json:"discord_notificator"`
 	Delegates          []mavryk.Address                              `json:"delegates,omitempty"`
+	RateLimit          RateLimitConfiguration                        `json:"rate_limit"`
+	PrivateApi         PrivateApiConfiguration                       `json:"private_api"`
+	ResponseCache      ResponseCacheConfiguration                    `json:"response_cache"`
 	LogLevel           slog.Level                                    `json:"-"`
 	Listen             string                                        `json:"-"`
 	PrivateListen      string                                        `json:"-"`
+	GrpcListen         string                                        `json:"-"`
+	// ed25519 private key responses are signed with, signing is disabled if empty
+	SigningKey string `json:"-"`
 }
 
 func LoadConfiguration(path string) (*Runtime, error) {
@@ -64,6 +111,17 @@ func LoadConfiguration(path string) (*Runtime, error) {
 	if runtimeConfig.Storage.Mode == constants.Rolling && runtimeConfig.Storage.StoredCycles == 0 {
 		runtimeConfig.Storage.StoredCycles = constants.STORED_CYCLES
 	}
+	// legacy rolling mode keeps only the detailed cycles
+	if runtimeConfig.Storage.Mode == constants.Rolling && runtimeConfig.Storage.Retention.DetailedCycles == 0 {
+		runtimeConfig.Storage.Retention.DetailedCycles = runtimeConfig.Storage.StoredCycles
+	}
+
+	if runtimeConfig.RateLimit.Max == 0 {
+		runtimeConfig.RateLimit.Max = constants.RATE_LIMIT_MAX
+	}
+	if runtimeConfig.RateLimit.Expiration == 0 {
+		runtimeConfig.RateLimit.Expiration = constants.RATE_LIMIT_EXPIRATION_SECONDS
+	}
 
 	if err = godotenv.Load(); err != nil {
 		slog.Info("error loading .env file, loading env variables directly from environment or if not found load the defaults", "error", err)
@@ -80,9 +138,84 @@ func LoadConfiguration(path string) (*Runtime, error) {
 		runtimeConfig.PrivateListen = constants.PRIVATE_LISTEN_DEFAULT
 	}
 
+	runtimeConfig.GrpcListen = os.Getenv(constants.GRPC_LISTEN)
+	if runtimeConfig.GrpcListen == "" {
+		runtimeConfig.GrpcListen = constants.GRPC_LISTEN_DEFAULT
+	}
+
+	runtimeConfig.SigningKey = os.Getenv(constants.SIGNING_KEY)
+
 	return &runtimeConfig, nil
 }
 
+// Validate checks the configuration for values the service can not run with
+func (r *Runtime) Validate() error {
+	errs := make([]error, 0)
+	if len(r.Providers) == 0 {
+		errs = append(errs, errors.New("at least one rpc provider is required"))
+	}
+	if len(r.MvktProviders) == 0 {
+		errs = append(errs, errors.New("at least one mvkt provider is required"))
+	}
+	if r.Database.Host == "" || r.Database.Database == "" {
+		errs = append(errs, errors.New("database host and name are required"))
+	}
+	switch r.Storage.Mode {
+	case "", constants.Archive, constants.Rolling: // empty mode keeps everything
+	default:
+		errs = append(errs, fmt.Errorf("unsupported storage mode %q", r.Storage.Mode))
+	}
+	if r.Storage.Retention.DetailedCycles < 0 {
+		errs = append(errs, errors.New("retention detailed_cycles can not be negative"))
+	}
+	if r.Storage.Retention.DetailedCycles == 0 && (r.Storage.Retention.KeepAggregates || r.Storage.Retention.ArchiveDirectory != "") {
+		errs = append(errs, errors.New("retention keep_aggregates and archive_directory require detailed_cycles"))
+	}
+	if r.SigningKey != "" {
+		if _, err := signing.NewSigner(r.SigningKey); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if r.RateLimit.Max < 0 || r.RateLimit.Expiration < 0 {
+		errs = append(errs, errors.New("rate limit values can not be negative"))
+	}
+	if r.PrivateListen != "" && len(r.PrivateApi.Clients) == 0 {
+		errs = append(errs, errors.New("private api requires at least one client"))
+	}
+	errs = append(errs, r.PrivateApi.validate()...)
+	if r.ResponseCache.Size < 0 {
+		errs = append(errs, errors.New("response cache size can not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *PrivateApiConfiguration) validate() []error {
+	errs := make([]error, 0)
+	names := make(map[string]bool)
+	for _, client := range p.Clients {
+		if client.Name == "" {
+			errs = append(errs, errors.New("private api client name is required"))
+		}
+		if names[client.Name] {
+			errs = append(errs, fmt.Errorf("private api client %q is configured twice", client.Name))
+		}
+		names[client.Name] = true
+		if client.Role != constants.RoleOperator && client.Role != constants.RoleAdmin {
+			errs = append(errs, fmt.Errorf("private api client %q has unsupported role %q", client.Name, client.Role))
+		}
+		if client.Token == "" && p.TlsClientCa == "" {
+			errs = append(errs, fmt.Errorf("private api client %q requires a token or tls_client_ca", client.Name))
+		}
+	}
+	if (p.TlsCertificate == "") != (p.TlsKey == "") {
+		errs = append(errs, errors.New("private api tls_certificate and tls_key are required together"))
+	}
+	if p.TlsClientCa != "" && p.TlsCertificate == "" {
+		errs = append(errs, errors.New("private api tls_client_ca requires tls_certificate and tls_key"))
+	}
+	return errs
+}
+
 func GetLogLevel(level string) slog.Level {
 	switch level {
 	case "debug":
@@ -1,7 +1,8 @@
 package constants
 
 const (
-	HTTP_CLIENT_TIMEOUT_SECONDS = 30
+	HTTP_CLIENT_TIMEOUT_SECONDS  = 30
+	HEALTH_CHECK_TIMEOUT_SECONDS = 5
 
 	CYCLE_FETCH_FREQUENCY_MINUTES = 5
 	MINIMUM_DIFF_TOLERANCE        = 1
@@ -18,8 +19,54 @@ const (
 	LISTEN_DEFAULT         = "127.0.0.1:3000"
 	PRIVATE_LISTEN         = "PRIVATE_LISTEN"
 	PRIVATE_LISTEN_DEFAULT = ""
+	SIGNING_KEY            = "SIGNING_KEY"
+	GRPC_LISTEN            = "GRPC_LISTEN"
+	GRPC_LISTEN_DEFAULT    = ""
 
 	STORED_CYCLES = 20
+
+	MUMAV_PER_MAV = 1_000_000
+
+	RATE_LIMIT_MAX                = 10
+	RATE_LIMIT_EXPIRATION_SECONDS = 30
+
+	RATE_LIMIT_CLEANUP_INTERVAL_SECONDS = 60
+
+	API_KEY_CACHE_SECONDS                = 30
+	API_KEY_USAGE_FLUSH_INTERVAL_SECONDS = 10
+
+	CONFIG_WATCH_INTERVAL_SECONDS = 5
+
+	BACKFILL_CYCLE_BATCH_SIZE = 2
+
+	EXPORT_ROW_GROUP_SIZE = 100_000
+
+	STORE_BATCH_SIZE = 100
+
+	TOP_DELEGATORS_DEFAULT_LIMIT = 10
+	TOP_DELEGATORS_MAX_LIMIT     = 100
+
+	QUERY_MAX_DELEGATES = 100
+	QUERY_MAX_CYCLES    = 50
+	QUERY_DEFAULT_LIMIT = 100
+	QUERY_MAX_LIMIT     = 1000
+
+	HISTORY_MAX_CYCLES = 1000
+
+	CACHE_IMMUTABLE_MAX_AGE_SECONDS = 365 * 24 * 60 * 60
+
+	AUDIT_DEFAULT_LIMIT = 100
+	AUDIT_MAX_LIMIT     = 1000
+
+	SNAPSHOT_FORMAT_VERSION = 1
+	ARCHIVE_CACHE_CYCLES    = 4
+	PROOF_TREE_CACHE_ROOTS  = 4
+
+	// delegated balance counts half towards the baking power since this cycle
+	DELEGATED_BAKING_POWER_HALVED_FROM_CYCLE = 748
+
+	EVENTS_BUFFER                     = 64
+	EVENTS_KEEPALIVE_INTERVAL_SECONDS = 15
 )
 
 type StorageKind string
@@ -28,3 +75,11 @@ const (
 	Archive StorageKind = "archive"
 	Rolling StorageKind = "rolling"
 )
+
+// PrivateApiRole of a private api client, admins can do everything operators can
+type PrivateApiRole string
+
+const (
+	RoleOperator PrivateApiRole = "operator"
+	RoleAdmin    PrivateApiRole = "admin"
+)
@@ -17,6 +17,21 @@ var (
 	ErrMinimumDelegatedBalanceNotFound      = errors.New("minimum delegated balance not found")
 	ErrFailedToFetchContractBalances        = errors.New("failed to fetch contract balances")
 	ErrDelegateNotRegistered                = errors.New("delegate not registered")
+	ErrDatabaseSchemaTooNew                 = errors.New("database schema is newer than supported")
+	ErrMigrationFailed                      = errors.New("migration failed")
+	ErrUnsupportedExportFormat              = errors.New("unsupported export format")
+	ErrInvalidSigningKey                    = errors.New("invalid signing key")
+	ErrInvalidSignature                     = errors.New("invalid signature")
+	ErrCommitmentOutdated                   = errors.New("stored delegation states changed since the cycle commitment")
+	ErrCyclePruned                          = errors.New("delegation states of the cycle were pruned")
+	ErrInvalidSnapshot                      = errors.New("invalid snapshot")
+	ErrSnapshotChecksumMismatch             = errors.Join(ErrInvalidSnapshot, errors.New("snapshot checksum mismatch"))
+	ErrProvidersChainMismatch               = errors.New("providers are not on the same chain")
+	ErrSnapshotChainMismatch                = errors.New("snapshot was created on a different chain")
+	ErrSnapshotSchemaNotSupported           = errors.New("snapshot schema version is not supported")
+	ErrInvalidCursor                        = errors.New("invalid cursor")
+	ErrAmbiguousCycleRange                  = errors.New("cycles of the range share a baking power origin")
+	ErrStakingParametersNotAvailable        = errors.New("staking parameters not available")
 
 	// notifications
 
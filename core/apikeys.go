@@ -0,0 +1,207 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+// cachedApiKey is a looked up key, key is nil for unknown and revoked keys
+type cachedApiKey struct {
+	key       *store.ApiKey
+	expiresAt time.Time
+}
+
+type apiKeyUsageKey struct {
+	id  int64
+	day string
+}
+
+// apiKeyUsage counts requests of api keys in memory, the counts are written in batches by flushApiKeyUsage.
+// Looked up keys are cached briefly so a request does not hit the database.
+type apiKeyUsage struct {
+	mtx  sync.Mutex
+	keys map[string]cachedApiKey
+	// requests of the current day by key id, stored requests plus the ones counted since
+	day   string
+	today map[int64]int64
+	// counted and not yet written
+	pending map[apiKeyUsageKey]int64
+}
+
+func newApiKeyUsage() *apiKeyUsage {
+	return &apiKeyUsage{
+		keys:    make(map[string]cachedApiKey),
+		today:   make(map[int64]int64),
+		pending: make(map[apiKeyUsageKey]int64),
+	}
+}
+
+// CreateApiKey returns the created key and its secret, the secret is only available here
+func (e *Engine) CreateApiKey(name string, rateLimitMax, rateLimitExpiration int, dailyQuota int64) (*store.ApiKey, string, error) {
+	return e.store.CreateApiKey(name, rateLimitMax, rateLimitExpiration, dailyQuota)
+}
+
+func (e *Engine) ListApiKeys() ([]store.ApiKeyWithUsage, error) {
+	if err := e.flushApiKeyUsage(); err != nil {
+		e.logger.Warn("failed to flush api key usage", "error", err.Error())
+	}
+	return e.store.ListApiKeys()
+}
+
+// RevokeApiKey revokes the key, replicas keep accepting it until their cached lookup expires
+func (e *Engine) RevokeApiKey(id int64) error {
+	if err := e.store.RevokeApiKey(id); err != nil {
+		return err
+	}
+	e.apiKeys.mtx.Lock()
+	defer e.apiKeys.mtx.Unlock()
+	for secret, cached := range e.apiKeys.keys {
+		if cached.key != nil && cached.key.Id == id {
+			delete(e.apiKeys.keys, secret)
+		}
+	}
+	return nil
+}
+
+// CachedApiKey returns the key if its lookup is cached, the key is nil if it was not found
+func (e *Engine) CachedApiKey(key string) (*store.ApiKey, bool) {
+	e.apiKeys.mtx.Lock()
+	defer e.apiKeys.mtx.Unlock()
+	cached, ok := e.apiKeys.keys[key]
+	if !ok || !time.Now().Before(cached.expiresAt) {
+		return nil, false
+	}
+	return cached.key, true
+}
+
+// GetApiKey returns constants.ErrNotFound for unknown and revoked keys, lookups including the failed ones
+// are cached for API_KEY_CACHE_SECONDS
+func (e *Engine) GetApiKey(key string) (*store.ApiKey, error) {
+	if apiKey, ok := e.CachedApiKey(key); ok {
+		if apiKey == nil {
+			return nil, constants.ErrNotFound
+		}
+		return apiKey, nil
+	}
+
+	now := time.Now()
+	apiKey, err := e.store.GetApiKey(key)
+	if err != nil && !errors.Is(err, constants.ErrNotFound) {
+		return nil, err
+	}
+	e.apiKeys.mtx.Lock()
+	defer e.apiKeys.mtx.Unlock()
+	for secret, cached := range e.apiKeys.keys {
+		if !now.Before(cached.expiresAt) {
+			delete(e.apiKeys.keys, secret)
+		}
+	}
+	e.apiKeys.keys[key] = cachedApiKey{key: apiKey, expiresAt: now.Add(constants.API_KEY_CACHE_SECONDS * time.Second)}
+	return apiKey, err
+}
+
+// RecordApiKeyUsage counts a request of the key unless its daily quota is used up, in which case
+// false is returned and the request is not counted
+func (e *Engine) RecordApiKeyUsage(apiKey *store.ApiKey) (bool, error) {
+	day := time.Now().UTC().Format(time.DateOnly)
+	usage := e.apiKeys
+
+	usage.mtx.Lock()
+	if usage.day != day {
+		usage.day = day
+		clear(usage.today)
+	}
+	_, known := usage.today[apiKey.Id]
+	usage.mtx.Unlock()
+	if !known {
+		stored, err := e.store.GetApiKeyUsage(apiKey.Id, day)
+		if err != nil {
+			return false, err
+		}
+		usage.mtx.Lock()
+		if _, ok := usage.today[apiKey.Id]; !ok && usage.day == day {
+			usage.today[apiKey.Id] = stored + usage.pending[apiKeyUsageKey{apiKey.Id, day}]
+		}
+		usage.mtx.Unlock()
+	}
+
+	usage.mtx.Lock()
+	defer usage.mtx.Unlock()
+	if apiKey.DailyQuota > 0 && usage.today[apiKey.Id] >= apiKey.DailyQuota {
+		return false, nil
+	}
+	usage.today[apiKey.Id]++
+	usage.pending[apiKeyUsageKey{apiKey.Id, day}]++
+	return true, nil
+}
+
+// flushApiKeyUsage writes the pending request counts, counts which failed to be written stay pending
+func (e *Engine) flushApiKeyUsage() error {
+	usage := e.apiKeys
+	usage.mtx.Lock()
+	pending := usage.pending
+	usage.pending = make(map[apiKeyUsageKey]int64)
+	usage.mtx.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	byDay := make(map[string]map[int64]int64)
+	for key, requests := range pending {
+		if byDay[key.day] == nil {
+			byDay[key.day] = make(map[int64]int64)
+		}
+		byDay[key.day][key.id] = requests
+	}
+	now := time.Now().UTC()
+	for day, requests := range byDay {
+		stored, err := e.store.AddApiKeyUsage(day, requests, now)
+
+		usage.mtx.Lock()
+		if err != nil {
+			for id, count := range requests {
+				usage.pending[apiKeyUsageKey{id, day}] += count
+			}
+			usage.mtx.Unlock()
+			return err
+		}
+		if usage.day == day {
+			// picks up requests counted by other replicas
+			for id, total := range stored {
+				usage.today[id] = total + usage.pending[apiKeyUsageKey{id, day}]
+			}
+		}
+		usage.mtx.Unlock()
+	}
+	return nil
+}
+
+func (e *Engine) flushApiKeyUsagePeriodically() {
+	ticker := time.NewTicker(constants.API_KEY_USAGE_FLUSH_INTERVAL_SECONDS * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.ctx.Done():
+			if err := e.flushApiKeyUsage(); err != nil {
+				e.logger.Warn("failed to flush api key usage", "error", err.Error())
+			}
+			return
+		case <-ticker.C:
+			if err := e.flushApiKeyUsage(); err != nil {
+				e.logger.Warn("failed to flush api key usage", "error", err.Error())
+			}
+		}
+	}
+}
+
+func (e *Engine) HitRateLimit(key string, expiration time.Duration) (int, time.Time, error) {
+	return e.store.HitRateLimit(key, expiration)
+}
+
+func (e *Engine) DeleteExpiredRateLimits() error {
+	return e.store.DeleteExpiredRateLimits()
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordApiKeyUsageSkipsRejectedRequests(t *testing.T) {
+	engine := &Engine{apiKeys: newApiKeyUsage(), logger: slog.Default()}
+	day := time.Now().UTC().Format(time.DateOnly)
+	engine.apiKeys.day = day
+	// the stored usage of the day is already known so the store is not read
+	engine.apiKeys.today[1] = 1
+	apiKey := &store.ApiKey{Id: 1, DailyQuota: 2}
+
+	counted, err := engine.RecordApiKeyUsage(apiKey)
+	require.NoError(t, err)
+	assert.True(t, counted)
+
+	for i := 0; i < 3; i++ {
+		counted, err = engine.RecordApiKeyUsage(apiKey)
+		require.NoError(t, err)
+		assert.False(t, counted)
+	}
+	assert.Equal(t, int64(2), engine.apiKeys.today[1])
+	assert.Equal(t, map[apiKeyUsageKey]int64{{1, day}: 1}, engine.apiKeys.pending)
+}
+
+func TestGetApiKeyCachesUnknownKeys(t *testing.T) {
+	engine := &Engine{apiKeys: newApiKeyUsage(), logger: slog.Default()}
+	engine.apiKeys.keys["pr_unknown"] = cachedApiKey{expiresAt: time.Now().Add(time.Minute)}
+
+	// the store is not set, a lookup would panic
+	_, err := engine.GetApiKey("pr_unknown")
+	assert.ErrorIs(t, err, constants.ErrNotFound)
+	apiKey, cached := engine.CachedApiKey("pr_unknown")
+	assert.True(t, cached)
+	assert.Nil(t, apiKey)
+}
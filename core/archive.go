@@ -0,0 +1,97 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/snapshot"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+// archivedCycle is a decoded cycle archive indexed by delegate
+type archivedCycle struct {
+	modTime time.Time
+	states  map[string]*store.StoredDelegationState
+}
+
+// archiveCache keeps the most recently used decoded cycle archives
+type archiveCache struct {
+	mtx    sync.Mutex
+	size   int
+	cycles map[int64]*archivedCycle
+	// least recently used first
+	order []int64
+}
+
+func newArchiveCache(size int) *archiveCache {
+	return &archiveCache{size: size, cycles: make(map[int64]*archivedCycle)}
+}
+
+// get returns the decoded archive of the cycle, the file is decoded again if it was replaced
+func (c *archiveCache) get(path string, cycle int64) (*archivedCycle, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, constants.ErrNotFound
+		}
+		return nil, err
+	}
+
+	c.mtx.Lock()
+	cached, ok := c.cycles[cycle]
+	if ok && cached.modTime.Equal(info.ModTime()) {
+		c.touch(cycle)
+		c.mtx.Unlock()
+		return cached, nil
+	}
+	c.mtx.Unlock()
+
+	archived, err := decodeArchive(path, info.ModTime())
+	if err != nil {
+		return nil, err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.cycles[cycle] = archived
+	c.touch(cycle)
+	for len(c.order) > c.size {
+		delete(c.cycles, c.order[0])
+		c.order = c.order[1:]
+	}
+	return archived, nil
+}
+
+func (c *archiveCache) touch(cycle int64) {
+	c.order = slices.DeleteFunc(c.order, func(item int64) bool { return item == cycle })
+	c.order = append(c.order, cycle)
+}
+
+func decodeArchive(path string, modTime time.Time) (*archivedCycle, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, constants.ErrNotFound
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	result := &archivedCycle{modTime: modTime, states: make(map[string]*store.StoredDelegationState)}
+	_, err = snapshot.Read(file, func(manifest *snapshot.Manifest) error {
+		return nil
+	}, func(_ int64, states []*store.StoredDelegationState) error {
+		for _, state := range states {
+			result.states[state.Delegate.String()] = state
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,11 @@
+package core
+
+import "github.com/mavryk-network/protocol-rewards/store"
+
+func (e *Engine) RecordAuditEntry(entry *store.AuditEntry) error {
+	return e.store.AppendAuditEntry(entry)
+}
+
+func (e *Engine) ListAuditEntries(limit int, before int64) ([]store.AuditEntry, error) {
+	return e.store.ListAuditEntries(limit, before)
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/samber/lo"
+)
+
+type BackfillOptions struct {
+	FromCycle int64
+	ToCycle   int64
+	// optional, limits the delegates on top of the configured delegate filter
+	Delegates []mavryk.Address
+	// number of cycles processed in parallel
+	Parallelism int
+	Force       bool
+	// ignore and drop the progress of previous runs of the same job
+	Restart bool
+}
+
+// Job identifies the backfill so interrupted runs with the same range and filter resume where they stopped
+func (o *BackfillOptions) Job() string {
+	delegates := lo.Map(o.Delegates, func(addr mavryk.Address, _ int) string { return addr.String() })
+	sort.Strings(delegates)
+	hash := sha256.Sum256([]byte(strings.Join(delegates, ",")))
+	return fmt.Sprintf("%d-%d-%s", o.FromCycle, o.ToCycle, hex.EncodeToString(hash[:4]))
+}
+
+type BackfillReport struct {
+	Job     string              `json:"job"`
+	From    int64               `json:"from"`
+	To      int64               `json:"to"`
+	Resumed []int64             `json:"resumed"`
+	Cycles  []*CycleFetchResult `json:"cycles"`
+	Errors  map[int64]string    `json:"errors"`
+}
+
+func (r *BackfillReport) HasFailures() bool {
+	return len(r.Errors) > 0 || lo.SomeBy(r.Cycles, func(result *CycleFetchResult) bool {
+		return len(result.Failures) > 0
+	})
+}
+
+// Backfill fetches delegation states of a range of cycles. Progress is checkpointed in the store
+// and cycles completed by a previous run of the same job are skipped.
+func (e *Engine) Backfill(ctx context.Context, options *BackfillOptions) (*BackfillReport, error) {
+	if options.Parallelism <= 0 {
+		options.Parallelism = constants.BACKFILL_CYCLE_BATCH_SIZE
+	}
+
+	job := options.Job()
+	if options.Restart {
+		if err := e.store.DeleteBackfillCheckpoints(job); err != nil {
+			return nil, err
+		}
+	}
+
+	completed, err := e.store.GetCompletedBackfillCycles(job)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BackfillReport{
+		Job:     job,
+		From:    options.FromCycle,
+		To:      options.ToCycle,
+		Resumed: make([]int64, 0),
+		Cycles:  make([]*CycleFetchResult, 0),
+		Errors:  make(map[int64]string),
+	}
+
+	cycles := make([]int64, 0, options.ToCycle-options.FromCycle+1)
+	for cycle := options.FromCycle; cycle <= options.ToCycle; cycle++ {
+		if slices.Contains(completed, cycle) {
+			report.Resumed = append(report.Resumed, cycle)
+			continue
+		}
+		cycles = append(cycles, cycle)
+	}
+	e.logger.Info("starting backfill", "job", job, "from", options.FromCycle, "to", options.ToCycle, "pending", len(cycles), "already_completed", len(report.Resumed))
+
+	fetchOptions := &FetchOptions{Force: options.Force}
+	err = runInParallel(ctx, cycles, options.Parallelism, func(ctx context.Context, cycle int64, mtx *sync.RWMutex) bool {
+		checkpoint := &store.BackfillCheckpoint{
+			Job:    job,
+			Cycle:  cycle,
+			Status: store.BackfillCheckpointStatusCompleted,
+		}
+
+		result, err := e.fetchCycleDelegationStates(ctx, cycle, 0, options.Delegates, fetchOptions)
+		switch {
+		case err != nil:
+			checkpoint.Status = store.BackfillCheckpointStatusFailed
+			checkpoint.Error = err.Error()
+		case len(result.Failures) > 0:
+			checkpoint.Status = store.BackfillCheckpointStatusFailed
+			checkpoint.Failures = len(result.Failures)
+		}
+
+		// interrupted cycles are not checkpointed, they are retried on the next run
+		if ctx.Err() == nil {
+			if err := e.store.StoreBackfillCheckpoint(checkpoint); err != nil {
+				e.logger.Error("failed to store backfill checkpoint", "job", job, "cycle", cycle, "error", err.Error())
+			}
+		}
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		if err != nil {
+			report.Errors[cycle] = err.Error()
+			return false
+		}
+		report.Cycles = append(report.Cycles, result)
+		return false
+	})
+	if err != nil {
+		return report, err
+	}
+
+	sort.Slice(report.Cycles, func(i, j int) bool { return report.Cycles[i].Cycle < report.Cycles[j].Cycle })
+	e.logger.Info("finished backfill", "job", job, "cycles", len(report.Cycles), "errors", len(report.Errors))
+	return report, ctx.Err()
+}
@@ -21,6 +21,7 @@ import (
 
 type rpcCollector struct {
 	rpcs     []*rpc.Client
+	rpcUrls  []string
 	mvktUrls []string
 	client   *http.Client
 }
@@ -77,6 +78,7 @@ func initRpcClient(ctx context.Context, rpcUrl string, transport http.RoundTripp
 func newRpcCollector(ctx context.Context, rpcUrls []string, mvktUrls []string, transport http.RoundTripper) (*rpcCollector, error) {
 	result := &rpcCollector{
 		rpcs:     make([]*rpc.Client, 0, len(rpcUrls)),
+		rpcUrls:  rpcUrls,
 		mvktUrls: mvktUrls,
 		client: &http.Client{
 			Timeout: constants.HTTP_CLIENT_TIMEOUT_SECONDS * time.Second,
@@ -180,6 +182,18 @@ func (engine *rpcCollector) GetCurrentProtocol() (mavryk.ProtocolHash, error) {
 	return params.Protocol, nil
 }
 
+// GetChainId returns the chain id of the providers, resolved when clients are initialized.
+// Fails if the providers do not agree on the chain.
+func (engine *rpcCollector) GetChainId() (mavryk.ChainIdHash, error) {
+	chainId := engine.rpcs[0].ChainId
+	for _, client := range engine.rpcs[1:] {
+		if !client.ChainId.Equal(chainId) {
+			return chainId, errors.Join(constants.ErrProvidersChainMismatch, fmt.Errorf("%s and %s", chainId, client.ChainId))
+		}
+	}
+	return chainId, nil
+}
+
 func (engine *rpcCollector) GetLastCompletedCycle(ctx context.Context) (cycle int64, lastBlockLevel int64, err error) {
 	head, err := attemptWithClients(engine.rpcs, func(client *rpc.Client) (*rpc.Block, error) {
 		return client.GetHeadBlock(ctx)
@@ -428,6 +442,8 @@ func makeBurnAndStakeBalanceUpdatesLast(updates []PRBalanceUpdate) []PRBalanceUp
 		case current.Kind == "freezer" && current.Category == "deposits":
 			fallthrough
 		case current.Kind == "burned" && current.Category == "storage fees":
+			previous.Deferred = DeferredBurnAndStake
+			current.Deferred = DeferredBurnAndStake
 			toBeLast = append(toBeLast, previous, current)
 		default:
 			regular = append(regular, previous, current)
@@ -568,6 +584,7 @@ func (engine *rpcCollector) getBlockBalanceUpdates(ctx context.Context, state *c
 	skip := false
 	for i, update := range blockBalanceUpdates {
 		if skip {
+			update.Deferred = DeferredUnstakeDeposit
 			cache = append(cache, update)
 			skip = false
 			continue
@@ -575,6 +592,7 @@ func (engine *rpcCollector) getBlockBalanceUpdates(ctx context.Context, state *c
 		if i+1 < len(blockBalanceUpdates) {
 			next := blockBalanceUpdates[i+1]
 			if update.Amount < 0 && next.Kind == "freezer" && next.Category == "deposits" {
+				update.Deferred = DeferredUnstakeDeposit
 				cache = append(cache, update)
 				skip = true
 				continue
@@ -591,8 +609,14 @@ func (engine *rpcCollector) getBlockBalanceUpdates(ctx context.Context, state *c
 }
 
 func (engine *rpcCollector) GetDelegationState(ctx context.Context, delegate *rpc.Delegate, cycle int64, lastBlockInTheCycle rpc.BlockID) (*common.DelegationState, error) {
+	return engine.getDelegationState(ctx, delegate, cycle, lastBlockInTheCycle, nil)
+}
+
+// reconstructs the delegation state, if explanation is not nil every step is recorded into it
+func (engine *rpcCollector) getDelegationState(ctx context.Context, delegate *rpc.Delegate, cycle int64, lastBlockInTheCycle rpc.BlockID, explanation *DelegationStateExplanation) (*common.DelegationState, error) {
 	blockLevelWithMinimumBalance := rpc.BlockLevel(delegate.MinDelegated.Level.Level)
 	targetAmount := delegate.MinDelegated.Amount
+	explanation.setTarget(blockLevelWithMinimumBalance.Int64(), targetAmount)
 
 	if blockLevelWithMinimumBalance == 0 {
 		slog.Debug("fetching delegation state - no minimum, taking last block balances", "blockLevelWithMinimumBalance", lastBlockInTheCycle, "delegate", delegate.Delegate.String())
@@ -600,6 +624,7 @@ func (engine *rpcCollector) GetDelegationState(ctx context.Context, delegate *rp
 		if err != nil {
 			return nil, err
 		}
+		explanation.setInitialState(state)
 		return state, constants.ErrDelegateHasNoMinimumDelegatedBalance
 	}
 
@@ -615,6 +640,8 @@ func (engine *rpcCollector) GetDelegationState(ctx context.Context, delegate *rp
 			Level: blockLevelWithMinimumBalance.Int64(),
 			Kind:  common.CreatedAtBlockBeginning,
 		}
+		explanation.setInitialState(state)
+		explanation.setMatched(state, -1)
 		return state, nil
 	}
 
@@ -622,14 +649,18 @@ func (engine *rpcCollector) GetDelegationState(ctx context.Context, delegate *rp
 	if err != nil {
 		return nil, err
 	}
+	// balances of delegation sources are added while collecting updates so we record the initial state only now
+	explanation.setInitialState(state)
 
 	found := false
 	for _, balanceUpdate := range allBalanceUpdates {
 		if !state.HasContractBalanceInfo(balanceUpdate.Address) {
+			explanation.addSkippedUpdate(balanceUpdate, "address not part of the delegation state")
 			continue
 		}
 
 		if constants.IgnoredBalanceUpdateKinds.Contains(balanceUpdate.Kind) {
+			explanation.addSkippedUpdate(balanceUpdate, "ignored balance update kind")
 			continue
 		}
 
@@ -651,6 +682,7 @@ func (engine *rpcCollector) GetDelegationState(ctx context.Context, delegate *rp
 		}
 
 		slog.Debug("balance update", "delegate", balanceUpdate.Delegate, "address", balanceUpdate.Address.String(), "delegated_balance", state.GetDelegatedBalance(), "amount", balanceUpdate.Amount, "target_amount", targetAmount, "diff", state.GetDelegatedBalance()-targetAmount)
+		updateIndex := explanation.addAppliedUpdate(balanceUpdate, state)
 
 		if abs(state.GetDelegatedBalance()-targetAmount) <= constants.MINIMUM_DIFF_TOLERANCE {
 			found = true
@@ -661,6 +693,7 @@ func (engine *rpcCollector) GetDelegationState(ctx context.Context, delegate *rp
 				InternalIndex: balanceUpdate.InternalIndex,
 				Kind:          balanceUpdate.Source,
 			}
+			explanation.setMatched(state, updateIndex)
 			break
 		}
 	}
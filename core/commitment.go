@@ -0,0 +1,176 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/merkle"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/samber/lo"
+)
+
+// committedTree is the merkle tree of a committed root with leaf indexes by delegator
+type committedTree struct {
+	tree       *merkle.Tree
+	leaves     []merkle.Leaf
+	delegators map[string][]int
+}
+
+func newCommittedTree(leaves []merkle.Leaf) *committedTree {
+	result := &committedTree{tree: merkle.Build(leaves), leaves: leaves, delegators: make(map[string][]int)}
+	for i, leaf := range leaves {
+		result.delegators[leaf.Delegator] = append(result.delegators[leaf.Delegator], i)
+	}
+	return result
+}
+
+// proofTreeCache keeps the most recently used committed trees by root
+type proofTreeCache struct {
+	mtx   sync.Mutex
+	size  int
+	trees map[string]*committedTree
+	// least recently used first
+	order []string
+}
+
+func newProofTreeCache(size int) *proofTreeCache {
+	return &proofTreeCache{size: size, trees: make(map[string]*committedTree)}
+}
+
+func (c *proofTreeCache) get(root string) (*committedTree, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	tree, ok := c.trees[root]
+	if ok {
+		c.touch(root)
+	}
+	return tree, ok
+}
+
+func (c *proofTreeCache) put(root string, tree *committedTree) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.trees[root] = tree
+	c.touch(root)
+	for len(c.order) > c.size {
+		delete(c.trees, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+func (c *proofTreeCache) touch(root string) {
+	c.order = slices.DeleteFunc(c.order, func(item string) bool { return item == root })
+	c.order = append(c.order, root)
+}
+
+func (e *Engine) loadCycleLeaves(cycle int64) ([]merkle.Leaf, error) {
+	leaves := make([]merkle.Leaf, 0)
+	err := e.store.ForEachDelegationStateBalance(cycle, cycle, nil, func(balance *store.DelegationStateBalanceWithStatus) error {
+		leaves = append(leaves, merkle.Leaf{
+			Cycle:             balance.Cycle,
+			Baker:             balance.Baker.String(),
+			Delegator:         balance.Delegator.String(),
+			DelegatedBalance:  balance.DelegatedBalance,
+			StakedBalance:     balance.StakedBalance,
+			OverstakedBalance: balance.OverstakedBalance,
+		})
+		return nil
+	})
+	// database collation may differ from byte order
+	merkle.SortLeaves(leaves)
+	return leaves, err
+}
+
+// CommitCycle computes the merkle root over all stored balances of the cycle and stores it as a new version
+// of the cycle summary if it changed. Earlier versions stay available.
+func (e *Engine) CommitCycle(cycle int64) (*store.CycleSummary, error) {
+	// taken before reading so states stored meanwhile mark the commitment outdated
+	computedAt := time.Now().UTC()
+	leaves, err := e.loadCycleLeaves(cycle)
+	if err != nil {
+		return nil, err
+	}
+	committed := newCommittedTree(leaves)
+
+	summary := &store.CycleSummary{
+		Cycle:      cycle,
+		MerkleRoot: committed.tree.Root().String(),
+		Leaves:     int64(committed.tree.Leaves()),
+		Bakers:     int64(len(lo.UniqBy(leaves, func(leaf merkle.Leaf) string { return leaf.Baker }))),
+		ComputedAt: computedAt,
+	}
+	if err := e.store.StoreCycleSummary(summary); err != nil {
+		return nil, err
+	}
+	e.proofTrees.put(summary.MerkleRoot, committed)
+	e.logger.Info("committed cycle", "cycle", cycle, "version", summary.Version, "root", summary.MerkleRoot, "leaves", summary.Leaves)
+	return summary, nil
+}
+
+// GetCycleSummary returns the version of the cycle commitment, 0 means the latest version
+func (e *Engine) GetCycleSummary(ctx context.Context, cycle, version int64) (*store.CycleSummary, error) {
+	cycle = e.getCollector().GetCycleBakingPowerOrigin(ctx, cycle)
+	return e.store.GetCycleSummary(cycle, version)
+}
+
+// getCommittedTree returns the tree of the committed root. It is rebuilt from the stored balances which
+// have to yield the same root, states stored again with unchanged balances keep the commitment valid.
+func (e *Engine) getCommittedTree(summary *store.CycleSummary) (*committedTree, error) {
+	if committed, ok := e.proofTrees.get(summary.MerkleRoot); ok {
+		return committed, nil
+	}
+
+	detailed, err := e.store.IsCycleDetailed(summary.Cycle)
+	if err != nil {
+		return nil, err
+	}
+	if !detailed {
+		return nil, constants.ErrCyclePruned
+	}
+
+	leaves, err := e.loadCycleLeaves(summary.Cycle)
+	if err != nil {
+		return nil, err
+	}
+	committed := newCommittedTree(leaves)
+	// kept under the root it has, it serves the next commitment of the cycle
+	e.proofTrees.put(committed.tree.Root().String(), committed)
+	if committed.tree.Root().String() != summary.MerkleRoot {
+		return nil, constants.ErrCommitmentOutdated
+	}
+	return committed, nil
+}
+
+// GetDelegatorInclusionProofs returns a proof against the version of the cycle commitment (0 for the latest)
+// for every baker the delegator had a balance with in the cycle
+func (e *Engine) GetDelegatorInclusionProofs(ctx context.Context, delegator mavryk.Address, cycle, version int64) ([]merkle.InclusionProof, error) {
+	summary, err := e.GetCycleSummary(ctx, cycle, version)
+	if err != nil {
+		return nil, err
+	}
+	committed, err := e.getCommittedTree(summary)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := committed.delegators[delegator.String()]
+	if len(indexes) == 0 {
+		return nil, errors.Join(constants.ErrNotFound, errors.New("delegator not found in the cycle"))
+	}
+	result := make([]merkle.InclusionProof, 0, len(indexes))
+	for _, i := range indexes {
+		result = append(result, merkle.InclusionProof{
+			Root:   committed.tree.Root(),
+			Leaf:   committed.leaves[i],
+			Index:  i,
+			Leaves: committed.tree.Leaves(),
+			Proof:  committed.tree.Proof(i),
+		})
+	}
+	return result, nil
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"github.com/mavryk-network/mvgo/mavryk"
 	"github.com/mavryk-network/protocol-rewards/common"
+	"github.com/mavryk-network/protocol-rewards/store"
 )
 
 var (
@@ -25,8 +26,18 @@ type PRBalanceUpdate struct {
 	Source        common.CreationInfoKind `json:"source"`
 
 	Delegate mavryk.Address `json:"delegate"`
+
+	// set if the update was moved behind the others before processing
+	Deferred DeferReason `json:"deferred,omitempty"`
 }
 
+type DeferReason string
+
+const (
+	DeferredBurnAndStake   DeferReason = "burn-and-stake-last"
+	DeferredUnstakeDeposit DeferReason = "unstake-deposit"
+)
+
 type PRBalanceUpdates []PRBalanceUpdate
 
 func (e PRBalanceUpdates) Len() int {
@@ -37,7 +48,31 @@ func (e PRBalanceUpdates) Add(updates ...PRBalanceUpdate) PRBalanceUpdates {
 	return append(e, updates...)
 }
 
+type DelegateFetchFailure struct {
+	Delegate mavryk.Address `json:"delegate"`
+	Error    string         `json:"error"`
+}
+
+type CycleFetchResult struct {
+	Cycle     int64                  `json:"cycle"`
+	Delegates int                    `json:"delegates"`
+	Failures  []DelegateFetchFailure `json:"failures"`
+}
+
 type FetchOptions struct {
 	Force bool
 	Debug bool
+	// recorded with the stored revision, derived from Force if empty
+	Reason store.RevisionReason
+}
+
+func (o *FetchOptions) revisionReason() store.RevisionReason {
+	switch {
+	case o.Reason != "":
+		return o.Reason
+	case o.Force:
+		return store.RevisionReasonForced
+	default:
+		return store.RevisionReasonAutomatic
+	}
 }
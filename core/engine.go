@@ -27,7 +27,17 @@ type Engine struct {
 	state       *state
 	notificator *notifications.DiscordNotificator
 	delegates   []mavryk.Address
+	transport   http.RoundTripper
+	retention   configuration.RetentionConfiguration
+	storageMode constants.StorageKind
+	archives    *archiveCache
+	proofTrees  *proofTreeCache
+	apiKeys     *apiKeyUsage
+	events      *eventBus
 	logger      *slog.Logger
+
+	// guards collector, notificator and delegates which can be swapped on configuration reload
+	mtx sync.RWMutex
 }
 
 type EngineOptions struct {
@@ -74,12 +84,20 @@ func NewEngine(ctx context.Context, config *configuration.Runtime, options *Engi
 		state:       newState(),
 		notificator: notificator,
 		delegates:   config.Delegates,
+		transport:   options.Transport,
+		retention:   config.Storage.Retention,
+		storageMode: config.Storage.Mode,
+		archives:    newArchiveCache(constants.ARCHIVE_CACHE_CYCLES),
+		proofTrees:  newProofTreeCache(constants.PROOF_TREE_CACHE_ROOTS),
+		apiKeys:     newApiKeyUsage(),
+		events:      newEventBus(),
 		logger:      slog.Default(), // TODO: replace with custom logger
 	}
 
 	if options.FetchAutomatically {
 		go result.fetchAutomatically()
 	}
+	go result.flushApiKeyUsagePeriodically()
 
 	return result, nil
 }
@@ -89,8 +107,6 @@ func (e *Engine) fetchDelegateDelegationStateInternal(ctx context.Context, deleg
 		options = &defaultFetchOptions
 	}
 
-	lastBlockInTheCycleId := rpc.BlockLevel(lastBlockInTheCycle)
-
 	if !options.Force && e.state.IsDelegateBeingFetched(cycle, delegateAddress) {
 		e.logger.Debug("delegate delegation state is already being fetched", "cycle", cycle, "delegate", delegateAddress.String())
 		return nil
@@ -107,21 +123,41 @@ func (e *Engine) fetchDelegateDelegationStateInternal(ctx context.Context, deleg
 	e.logger.Debug("fetching delegate delegation state", "cycle", cycle, "delegate", delegateAddress.String())
 	e.state.AddDelegateBeingFetched(cycle, delegateAddress)
 	defer e.state.RemoveCycleBeingFetched(cycle, delegateAddress)
+	e.publishDelegateEvent(EventDelegateStarted, cycle, delegateAddress, nil)
 
-	delegate, err := e.collector.GetDelegateFromCycle(ctx, lastBlockInTheCycleId, delegateAddress)
+	storableState, err := e.collectDelegationState(ctx, delegateAddress, cycle, lastBlockInTheCycle, options)
+	if err == nil {
+		storableState.Reason = options.revisionReason()
+		err = e.store.StoreDelegationState(storableState)
+	}
 	if err != nil {
-		e.logger.Debug("failed to get delegate from", "cycle", cycle, "delegateAddress", delegateAddress, "error", err)
+		e.publishDelegateEvent(EventDelegateFailed, cycle, delegateAddress, err)
 		return err
 	}
+	e.publishDelegateEvent(EventDelegateStored, cycle, delegateAddress, nil)
+	return nil
+}
 
-	state, err := e.collector.GetDelegationState(ctx, delegate, cycle, lastBlockInTheCycleId)
+// collects the delegation state of the delegate from the chain without storing it
+func (e *Engine) collectDelegationState(ctx context.Context, delegateAddress mavryk.Address, cycle, lastBlockInTheCycle int64, options *FetchOptions) (*store.StoredDelegationState, error) {
+	lastBlockInTheCycleId := rpc.BlockLevel(lastBlockInTheCycle)
+
+	// keep using the same collector for the whole job even if it is swapped by a reload meanwhile
+	collector := e.getCollector()
+	delegate, err := collector.GetDelegateFromCycle(ctx, lastBlockInTheCycleId, delegateAddress)
+	if err != nil {
+		e.logger.Debug("failed to get delegate from", "cycle", cycle, "delegateAddress", delegateAddress, "error", err)
+		return nil, err
+	}
+
+	state, err := collector.GetDelegationState(ctx, delegate, cycle, lastBlockInTheCycleId)
 	var storableState *store.StoredDelegationState
 	switch {
 	case err != nil && err != constants.ErrDelegateHasNoMinimumDelegatedBalance:
 		if options.Debug && errors.Is(err, constants.ErrMinimumDelegatedBalanceNotFound) {
 			panic(err)
 		}
-		return err
+		return nil, err
 	case err == constants.ErrDelegateHasNoMinimumDelegatedBalance:
 		storableState = store.CreateStoredDelegationStateFromDelegationState(state)
 		storableState.Status = store.DelegationStateStatusMinimumNotAvailable
@@ -130,12 +166,12 @@ func (e *Engine) fetchDelegateDelegationStateInternal(ctx context.Context, deleg
 	}
 	e.logger.Debug("fetched delegate delegation state", "cycle", cycle, "delegate", delegateAddress.String(), "baking_power", state.GetBakingPower())
 
-	return e.store.StoreDelegationState(storableState)
+	return storableState, nil
 }
 
 func (e *Engine) FetchDelegateDelegationState(ctx context.Context, delegateAddress mavryk.Address, cycle, lastBlockInTheCycle int64, options *FetchOptions) error {
 	e.logger.Info("fetching delegate delegation state", "cycle", cycle, "delegate", delegateAddress.String(), "force_fetch", options)
-	lastCompletedCycle, _, err := e.collector.GetLastCompletedCycle(ctx)
+	lastCompletedCycle, _, err := e.getCollector().GetLastCompletedCycle(ctx)
 	if err != nil {
 		e.logger.Error("failed to get last completed cycle", "error", err)
 		return err
@@ -145,7 +181,7 @@ func (e *Engine) FetchDelegateDelegationState(ctx context.Context, delegateAddre
 	}
 
 	if lastBlockInTheCycle == 0 {
-		lastBlockInTheCycle = e.collector.determineLastBlockOfCycle(cycle)
+		lastBlockInTheCycle = e.getCollector().determineLastBlockOfCycle(cycle)
 	}
 
 	if err := e.fetchDelegateDelegationStateInternal(ctx, delegateAddress, cycle, lastBlockInTheCycle, options); err != nil {
@@ -156,51 +192,72 @@ func (e *Engine) FetchDelegateDelegationState(ctx context.Context, delegateAddre
 	return nil
 }
 
-func (e *Engine) getDelegates(ctx context.Context, lastBlockInTheCycle int64) ([]mavryk.Address, error) {
-	delegates, err := e.collector.GetActiveDelegatesFromCycle(ctx, rpc.BlockLevel(lastBlockInTheCycle))
+// returns active delegates of the cycle limited by the configured delegate filter and the additional filter if passed
+func (e *Engine) getDelegates(ctx context.Context, lastBlockInTheCycle int64, additionalFilter ...mavryk.Address) ([]mavryk.Address, error) {
+	delegates, err := e.getCollector().GetActiveDelegatesFromCycle(ctx, rpc.BlockLevel(lastBlockInTheCycle))
 	if err != nil {
 		e.logger.Error("failed to fetch active delegates from block", "block", lastBlockInTheCycle, "error", err.Error())
 		return nil, err
 	}
 
-	if len(e.delegates) == 0 {
-		return delegates, nil
+	filter := e.getDelegateFilter()
+	if len(filter) > 0 {
+		delegates = lo.Filter(delegates, func(d mavryk.Address, _ int) bool {
+			return slices.Contains(filter, d)
+		})
 	}
 
-	delegates = lo.Filter(delegates, func(d mavryk.Address, _ int) bool {
-		return slices.Contains(e.delegates, d)
-	})
+	if len(additionalFilter) > 0 {
+		delegates = lo.Filter(delegates, func(d mavryk.Address, _ int) bool {
+			return slices.Contains(additionalFilter, d)
+		})
+	}
 
 	return delegates, nil
 }
 
-func (e *Engine) FetchCycleDelegationStates(ctx context.Context, cycle, lastBlockInTheCycle int64, options *FetchOptions) error {
+// FetchCycleDelegationStates fetches delegation states of all active delegates (respecting the delegate filter) in the cycle.
+// Failures of individual delegates do not fail the cycle, they are reported in the result.
+func (e *Engine) FetchCycleDelegationStates(ctx context.Context, cycle, lastBlockInTheCycle int64, options *FetchOptions) (*CycleFetchResult, error) {
+	return e.fetchCycleDelegationStates(ctx, cycle, lastBlockInTheCycle, nil, options)
+}
+
+func (e *Engine) fetchCycleDelegationStates(ctx context.Context, cycle, lastBlockInTheCycle int64, delegateFilter []mavryk.Address, options *FetchOptions) (*CycleFetchResult, error) {
 	e.logger.Info("fetching cycle delegation states", "cycle", cycle, "options", options)
-	lastCompletedCycle, _, err := e.collector.GetLastCompletedCycle(ctx)
+	lastCompletedCycle, _, err := e.getCollector().GetLastCompletedCycle(ctx)
 	if err != nil {
 		e.logger.Error("failed to fetch last completed cycle number", "error", err.Error())
-		return err
+		return nil, err
 	}
 	if cycle > lastCompletedCycle {
 		e.logger.Error("cycle did not end yet", "cycle", cycle, "last_completed_cycle", lastCompletedCycle)
-		return constants.ErrCycleDidNotEndYet
+		return nil, constants.ErrCycleDidNotEndYet
 	}
 
 	if lastBlockInTheCycle == 0 {
-		lastBlockInTheCycle = e.collector.determineLastBlockOfCycle(cycle)
+		lastBlockInTheCycle = e.getCollector().determineLastBlockOfCycle(cycle)
 	}
 
-	delegates, err := e.getDelegates(ctx, lastBlockInTheCycle)
+	delegates, err := e.getDelegates(ctx, lastBlockInTheCycle, delegateFilter...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	result := &CycleFetchResult{
+		Cycle:     cycle,
+		Delegates: len(delegates),
+		Failures:  make([]DelegateFetchFailure, 0),
+	}
 	err = runInParallel(ctx, delegates, constants.DELEGATE_FETCH_BATCH_SIZE, func(ctx context.Context, item mavryk.Address, mtx *sync.RWMutex) bool {
 		err := e.fetchDelegateDelegationStateInternal(ctx, item, cycle, lastBlockInTheCycle, options)
 		if err != nil {
 			e.logger.Error("failed to fetch delegate delegation state", "cycle", cycle, "delegate", item.String(), "error", err.Error())
 			msg := fmt.Sprintf("Failed to fetch delegate %s delegation state on cycle %d", item.String(), cycle)
-			notifications.Notify(e.notificator, msg)
+			notifications.Notify(e.getNotificator(), msg)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			result.Failures = append(result.Failures, DelegateFetchFailure{Delegate: item, Error: err.Error()})
 			return false
 		}
 		e.logger.Info("finished fetching delegate delegation state", "cycle", cycle, "delegate", item.String())
@@ -209,10 +266,72 @@ func (e *Engine) FetchCycleDelegationStates(ctx context.Context, cycle, lastBloc
 
 	if err != nil {
 		e.logger.Error("failed to fetch cycle", "cycle", cycle, "error", err.Error())
-		return err
+		return nil, err
 	}
-	e.logger.Info("finished fetching cycle delegation states", "cycle", cycle)
-	notifications.Notify(e.notificator, fmt.Sprintf("Finished fetching cycle %d delegation states", cycle))
+	// only complete cycles are committed, a root must not change after it was published.
+	// Fetching selected delegates (backfill -delegates) leaves the rest of the cycle as it was.
+	if len(result.Failures) == 0 && len(delegateFilter) == 0 {
+		if _, err := e.CommitCycle(cycle); err != nil {
+			e.logger.Error("failed to commit cycle", "cycle", cycle, "error", err.Error())
+		}
+		if err := e.CacheStatistics(cycle); err != nil {
+			e.logger.Error("failed to cache cycle statistics", "cycle", cycle, "error", err.Error())
+		}
+	}
+	e.logger.Info("finished fetching cycle delegation states", "cycle", cycle, "failures", len(result.Failures))
+	e.publish(Event{Kind: EventCycleCompleted, Cycle: cycle, Delegates: result.Delegates, Failures: len(result.Failures)})
+	notifications.Notify(e.getNotificator(), fmt.Sprintf("Finished fetching cycle %d delegation states", cycle))
+	return result, nil
+}
+
+func (e *Engine) getCollector() *rpcCollector {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	return e.collector
+}
+
+func (e *Engine) getNotificator() *notifications.DiscordNotificator {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	return e.notificator
+}
+
+func (e *Engine) getDelegateFilter() []mavryk.Address {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+	return e.delegates
+}
+
+// Reload applies reloadable configuration to the running engine.
+// Jobs which are already running keep the collector they started with.
+func (e *Engine) Reload(ctx context.Context, config *configuration.Runtime) error {
+	e.mtx.RLock()
+	collector := e.collector
+	e.mtx.RUnlock()
+
+	if !slices.Equal(collector.rpcUrls, config.Providers) || !slices.Equal(collector.mvktUrls, config.MvktProviders) {
+		var err error
+		collector, err = newRpcCollector(ctx, config.Providers, config.MvktProviders, e.transport)
+		if err != nil {
+			e.logger.Error("failed to rebuild RPC collector, keeping the current one", "error", err)
+			return err
+		}
+	}
+
+	// replaced even if missing, removing the webhook turns notifications off
+	var notificator *notifications.DiscordNotificator
+	if err := notifications.ValidateDiscordConfiguration(&config.DiscordNotificator); err == nil {
+		if notificator, err = notifications.InitDiscordNotificator(&config.DiscordNotificator); err != nil {
+			e.logger.Warn("failed to reinitialize notificator", "error", err)
+		}
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.collector = collector
+	e.notificator = notificator
+	e.delegates = config.Delegates
+	e.logger.Info("engine configuration reloaded", "providers", len(config.Providers), "delegates", len(config.Delegates))
 	return nil
 }
 
@@ -220,14 +339,43 @@ func (e *Engine) IsDelegateBeingFetched(cycle int64, delegate mavryk.Address) bo
 	return e.state.IsDelegateBeingFetched(cycle, delegate)
 }
 
+// GetDelegationState returns the stored delegation state, cycles pruned by the retention policy are looked up in the archive
 func (e *Engine) GetDelegationState(ctx context.Context, delegate mavryk.Address, cycle int64) (*store.StoredDelegationState, error) {
-	cycle = e.collector.GetCycleBakingPowerOrigin(ctx, cycle)
-	return e.store.GetDelegationState(delegate, cycle)
+	cycle = e.getCollector().GetCycleBakingPowerOrigin(ctx, cycle)
+	state, err := e.store.GetDelegationState(delegate, cycle)
+	if errors.Is(err, constants.ErrNotFound) {
+		if archived, archiveErr := e.getArchivedDelegationState(delegate, cycle); archiveErr == nil {
+			return archived, nil
+		}
+	}
+	return state, err
 }
 
 func (e *Engine) IsDelegationStateAvailable(ctx context.Context, delegate mavryk.Address, cycle int64) (bool, error) {
-	cycle = e.collector.GetCycleBakingPowerOrigin(ctx, cycle)
-	return e.store.IsDelegationStateAvailable(delegate, cycle)
+	cycle = e.getCollector().GetCycleBakingPowerOrigin(ctx, cycle)
+	available, err := e.store.IsDelegationStateAvailable(delegate, cycle)
+	if err != nil || available {
+		return available, err
+	}
+	// same fallback as GetDelegationState
+	if _, archiveErr := e.getArchivedDelegationState(delegate, cycle); archiveErr == nil {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (e *Engine) GetDelegatorBalances(ctx context.Context, delegator mavryk.Address, cycle int64) ([]store.DelegationStateBalance, error) {
+	cycle = e.getCollector().GetCycleBakingPowerOrigin(ctx, cycle)
+	return e.store.GetDelegatorBalances(delegator, cycle)
+}
+
+func (e *Engine) GetTopDelegators(ctx context.Context, delegate mavryk.Address, cycle int64, limit int) ([]store.DelegationStateBalance, error) {
+	cycle = e.getCollector().GetCycleBakingPowerOrigin(ctx, cycle)
+	return e.store.GetTopDelegators(delegate, cycle, limit)
+}
+
+func (e *Engine) GetLastFetchedCycle() (int64, error) {
+	return e.store.GetLastFetchedCycle()
 }
 
 func (e *Engine) Statisticts(ctx context.Context, cycle int64) (*common.CycleStatistics, error) {
@@ -243,7 +391,7 @@ func (e *Engine) fetchAutomatically() {
 			default:
 				time.Sleep(constants.CYCLE_FETCH_FREQUENCY_MINUTES * time.Minute)
 
-				lastOnChainCompletedCycle, lastBlockInTheCycle, err := e.collector.GetLastCompletedCycle(e.ctx)
+				lastOnChainCompletedCycle, lastBlockInTheCycle, err := e.getCollector().GetLastCompletedCycle(e.ctx)
 				if err != nil {
 					e.logger.Error("failed to fetch last completed cycle number", "error", err.Error())
 					return
@@ -278,10 +426,10 @@ func (e *Engine) fetchAutomatically() {
 						lastBlock = lastBlockInTheCycle
 					}
 
-					if err = e.FetchCycleDelegationStates(e.ctx, cycle, lastBlock, nil); err != nil {
+					if _, err = e.FetchCycleDelegationStates(e.ctx, cycle, lastBlock, nil); err != nil {
 						e.logger.Error("failed to fetch cycle delegation states", "cycle", cycle, "error", err.Error())
 					}
-					if err = e.store.PruneDelegationState(cycle); err != nil {
+					if _, err = e.ApplyRetention(cycle); err != nil {
 						e.logger.Error("failed to prune cycles out", "error", err.Error())
 					}
 				}
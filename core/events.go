@@ -0,0 +1,111 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+type EventKind string
+
+const (
+	EventDelegateStarted EventKind = "delegate_started"
+	EventDelegateStored  EventKind = "delegate_stored"
+	EventDelegateFailed  EventKind = "delegate_failed"
+	EventCycleCompleted  EventKind = "cycle_completed"
+	EventPruned          EventKind = "pruned"
+)
+
+// Event is published on the engine event bus while delegation states are fetched and pruned
+type Event struct {
+	Kind  EventKind `json:"kind"`
+	Cycle int64     `json:"cycle"`
+	// set for delegate events only
+	Delegate *mavryk.Address `json:"delegate,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	// set for cycle completed events only
+	Delegates int       `json:"delegates,omitempty"`
+	Failures  int       `json:"failures,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// EventFilter limits delegate events to the delegates, cycle and prune events always pass
+type EventFilter []mavryk.Address
+
+func (f EventFilter) Match(event *Event) bool {
+	if len(f) == 0 || event.Delegate == nil {
+		return true
+	}
+	for _, delegate := range f {
+		if delegate.Equal(*event.Delegate) {
+			return true
+		}
+	}
+	return false
+}
+
+type eventBus struct {
+	mtx         sync.Mutex
+	next        int
+	subscribers map[int]chan Event
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		subscribers: make(map[int]chan Event),
+	}
+}
+
+// SubscribeEvents returns a channel receiving events until the returned cancel function is called.
+// Events are dropped for subscribers which do not keep up.
+func (e *Engine) SubscribeEvents() (<-chan Event, func()) {
+	bus := e.events
+	bus.mtx.Lock()
+	defer bus.mtx.Unlock()
+
+	id := bus.next
+	bus.next++
+	ch := make(chan Event, constants.EVENTS_BUFFER)
+	bus.subscribers[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			bus.mtx.Lock()
+			defer bus.mtx.Unlock()
+			delete(bus.subscribers, id)
+			close(ch)
+		})
+	}
+}
+
+func (e *Engine) publish(event Event) {
+	event.Time = time.Now().UTC()
+	bus := e.events
+	bus.mtx.Lock()
+	defer bus.mtx.Unlock()
+	for _, ch := range bus.subscribers {
+		select {
+		case ch <- event:
+		default:
+			e.logger.Warn("event subscriber is too slow, dropping event", "kind", event.Kind, "cycle", event.Cycle)
+		}
+	}
+}
+
+func (e *Engine) publishDelegateEvent(kind EventKind, cycle int64, delegate mavryk.Address, err error) {
+	event := Event{Kind: kind, Cycle: cycle, Delegate: &delegate}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	e.publish(event)
+}
+
+// OnDelegationStatesChanged registers a listener called synchronously after stored states were written or pruned,
+// unlike events it never misses a change
+func (e *Engine) OnDelegationStatesChanged(listener store.ChangeListener) {
+	e.store.OnDelegationStatesChanged(listener)
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventBus(t *testing.T) {
+	engine := &Engine{events: newEventBus(), logger: slog.Default()}
+	baker := mavryk.MustParseAddress("mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL")
+	other := mavryk.MustParseAddress("mv1CtCq3D2RrCxx6VL5aMTkfq8tYLSK4sXmN")
+
+	events, cancel := engine.SubscribeEvents()
+	engine.publishDelegateEvent(EventDelegateStored, 10, baker, nil)
+	engine.publish(Event{Kind: EventCycleCompleted, Cycle: 10, Delegates: 1})
+
+	event := <-events
+	assert.Equal(t, EventDelegateStored, event.Kind)
+	assert.True(t, baker.Equal(*event.Delegate))
+	assert.False(t, event.Time.IsZero())
+
+	filter := EventFilter{other}
+	assert.False(t, filter.Match(&event))
+	event = <-events
+	assert.True(t, filter.Match(&event), "cycle events pass any filter")
+
+	cancel()
+	_, ok := <-events
+	assert.False(t, ok)
+	// publishing without subscribers or after cancel must not block
+	engine.publish(Event{Kind: EventPruned, Cycle: 1})
+	cancel()
+}
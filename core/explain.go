@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/mvgo/rpc"
+	"github.com/mavryk-network/protocol-rewards/common"
+	"github.com/mavryk-network/protocol-rewards/constants"
+)
+
+type ExplainedBalanceUpdate struct {
+	PRBalanceUpdate
+	Applied    bool   `json:"applied"`
+	SkipReason string `json:"skip_reason,omitempty"`
+	// running delegated total after the update
+	DelegatedBalance int64 `json:"delegated_balance"`
+	Diff             int64 `json:"diff"`
+}
+
+// DelegationStateExplanation is a trace of the delegation state reconstruction
+type DelegationStateExplanation struct {
+	Delegate                mavryk.Address                     `json:"delegate"`
+	Cycle                   int64                              `json:"cycle"`
+	LastBlockLevel          int64                              `json:"last_block_level"`
+	BlockWithMinimum        int64                              `json:"block_with_minimum"`
+	TargetAmount            int64                              `json:"target_amount"`
+	InitialBalances         common.DelegationStateBalances     `json:"initial_balances"`
+	InitialDelegatedBalance int64                              `json:"initial_delegated_balance"`
+	Updates                 []ExplainedBalanceUpdate           `json:"updates"`
+	Matched                 bool                               `json:"matched"`
+	MatchedUpdateIndex      *int                               `json:"matched_update_index"`
+	CreatedAt               common.DelegationStateCreationInfo `json:"created_at"`
+	Error                   string                             `json:"error,omitempty"`
+}
+
+// all methods are nil safe so the reconstruction can record into explanation unconditionally
+
+func (t *DelegationStateExplanation) setTarget(blockWithMinimum, targetAmount int64) {
+	if t == nil {
+		return
+	}
+	t.BlockWithMinimum = blockWithMinimum
+	t.TargetAmount = targetAmount
+}
+
+func (t *DelegationStateExplanation) setInitialState(state *common.DelegationState) {
+	if t == nil {
+		return
+	}
+	t.InitialBalances = state.GetBalanceInfos()
+	t.InitialDelegatedBalance = state.GetDelegatedBalance()
+}
+
+func (t *DelegationStateExplanation) addSkippedUpdate(update PRBalanceUpdate, reason string) {
+	if t == nil {
+		return
+	}
+	t.Updates = append(t.Updates, ExplainedBalanceUpdate{
+		PRBalanceUpdate: update,
+		SkipReason:      reason,
+	})
+}
+
+func (t *DelegationStateExplanation) addAppliedUpdate(update PRBalanceUpdate, state *common.DelegationState) int {
+	if t == nil {
+		return -1
+	}
+	delegated := state.GetDelegatedBalance()
+	t.Updates = append(t.Updates, ExplainedBalanceUpdate{
+		PRBalanceUpdate:  update,
+		Applied:          true,
+		DelegatedBalance: delegated,
+		Diff:             delegated - t.TargetAmount,
+	})
+	return len(t.Updates) - 1
+}
+
+// index -1 means the minimum was matched at the beginning of the block
+func (t *DelegationStateExplanation) setMatched(state *common.DelegationState, index int) {
+	if t == nil {
+		return
+	}
+	t.Matched = true
+	t.CreatedAt = state.CreatedAt
+	if index >= 0 {
+		t.MatchedUpdateIndex = &index
+	}
+}
+
+// ExplainDelegationState reconstructs the delegation state of the delegate and returns a trace of every step.
+// Nothing is stored. The trace is returned even if the minimum delegated balance was not matched.
+func (e *Engine) ExplainDelegationState(ctx context.Context, delegateAddress mavryk.Address, cycle, lastBlockInTheCycle int64) (*DelegationStateExplanation, error) {
+	collector := e.getCollector()
+	if lastBlockInTheCycle == 0 {
+		lastBlockInTheCycle = collector.determineLastBlockOfCycle(cycle)
+	}
+	lastBlockInTheCycleId := rpc.BlockLevel(lastBlockInTheCycle)
+
+	delegate, err := collector.GetDelegateFromCycle(ctx, lastBlockInTheCycleId, delegateAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &DelegationStateExplanation{
+		Delegate:       delegateAddress,
+		Cycle:          cycle,
+		LastBlockLevel: lastBlockInTheCycle,
+		Updates:        make([]ExplainedBalanceUpdate, 0),
+	}
+
+	_, err = collector.getDelegationState(ctx, delegate, cycle, lastBlockInTheCycleId, explanation)
+	switch {
+	case err == nil:
+	case errors.Is(err, constants.ErrMinimumDelegatedBalanceNotFound), errors.Is(err, constants.ErrDelegateHasNoMinimumDelegatedBalance):
+		explanation.Error = err.Error()
+	default:
+		return nil, err
+	}
+	return explanation, nil
+}
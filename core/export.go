@@ -0,0 +1,13 @@
+package core
+
+import (
+	"context"
+	"io"
+
+	"github.com/mavryk-network/protocol-rewards/export"
+)
+
+// ExportDelegationStates streams flattened delegation states matching the filter into w
+func (e *Engine) ExportDelegationStates(ctx context.Context, filter *export.Filter, format export.Format, w io.Writer) (int, error) {
+	return export.Export(ctx, e.store, filter, format, w)
+}
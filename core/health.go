@@ -0,0 +1,151 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mavryk-network/mvgo/rpc"
+	"github.com/mavryk-network/protocol-rewards/constants"
+)
+
+type ReadinessCheck struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Readiness struct {
+	Ready  bool             `json:"ready"`
+	Checks []ReadinessCheck `json:"checks"`
+}
+
+type Status struct {
+	Version            string `json:"version"`
+	Codename           string `json:"codename"`
+	ChainId            string `json:"chain_id"`
+	LastFetchedCycle   int64  `json:"last_fetched_cycle"`
+	LastCompletedCycle int64  `json:"last_completed_cycle"`
+	// completed cycles not fetched yet
+	Lag               int64                 `json:"lag"`
+	DelegatesInFlight int                   `json:"delegates_in_flight"`
+	StorageMode       constants.StorageKind `json:"storage_mode"`
+	DetailedCycles    int                   `json:"detailed_cycles"`
+	// set if part of the status could not be determined
+	Errors []string `json:"errors,omitempty"`
+}
+
+// checkRpcs succeeds if at least one provider returns its head
+func (engine *rpcCollector) checkRpcs(ctx context.Context) error {
+	_, err := attemptOnce(engine.rpcs, func(client *rpc.Client) (*rpc.Block, error) {
+		return client.GetHeadBlock(ctx)
+	})
+	return err
+}
+
+// checkMvkt succeeds if at least one mvkt provider answers
+func (engine *rpcCollector) checkMvkt(ctx context.Context) error {
+	errs := make([]error, 0, len(engine.mvktUrls))
+	for _, url := range engine.mvktUrls {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"v1/head", nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resp, err := engine.client.Do(req)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			errs = append(errs, fmt.Errorf("%s: unexpected status %d", url, resp.StatusCode))
+			continue
+		}
+		return nil
+	}
+	if len(errs) == 0 {
+		return errors.New("no mvkt providers configured")
+	}
+	return errors.Join(errs...)
+}
+
+func runCheck(ctx context.Context, name string, check func(ctx context.Context) error) ReadinessCheck {
+	ctx, cancel := context.WithTimeout(ctx, constants.HEALTH_CHECK_TIMEOUT_SECONDS*time.Second)
+	defer cancel()
+	if err := check(ctx); err != nil {
+		return ReadinessCheck{Name: name, Error: err.Error()}
+	}
+	return ReadinessCheck{Name: name, Healthy: true}
+}
+
+// CheckReadiness checks the database and that at least one rpc and mvkt provider is reachable
+func (e *Engine) CheckReadiness(ctx context.Context) *Readiness {
+	collector := e.getCollector()
+	result := &Readiness{
+		Ready: true,
+		Checks: []ReadinessCheck{
+			runCheck(ctx, "database", e.store.Ping),
+			runCheck(ctx, "rpc", collector.checkRpcs),
+			runCheck(ctx, "mvkt", collector.checkMvkt),
+		},
+	}
+	for _, check := range result.Checks {
+		result.Ready = result.Ready && check.Healthy
+	}
+	return result
+}
+
+func (e *Engine) GetStatus(ctx context.Context) *Status {
+	result := &Status{
+		Version:           constants.VERSION,
+		Codename:          constants.CODENAME,
+		DelegatesInFlight: e.state.CountDelegatesBeingFetched(),
+		StorageMode:       e.storageMode,
+		DetailedCycles:    e.retention.DetailedCycles,
+		Errors:            make([]string, 0),
+	}
+	if result.StorageMode == "" {
+		result.StorageMode = constants.Archive
+	}
+
+	collector := e.getCollector()
+	if chainId, err := collector.GetChainId(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	} else {
+		result.ChainId = chainId.String()
+	}
+
+	lastFetchedCycle, err := e.store.GetLastFetchedCycle()
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	result.LastFetchedCycle = lastFetchedCycle
+
+	ctx, cancel := context.WithTimeout(ctx, constants.HEALTH_CHECK_TIMEOUT_SECONDS*time.Second)
+	defer cancel()
+	// a single attempt per provider, the retries of the collector would block the response
+	head, err := attemptOnce(collector.rpcs, func(client *rpc.Client) (*rpc.Block, error) {
+		return client.GetHeadBlock(ctx)
+	})
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	} else {
+		result.LastCompletedCycle = head.GetLevelInfo().Cycle - 1
+		result.Lag = max(result.LastCompletedCycle-result.LastFetchedCycle, 0)
+	}
+	return result
+}
+
+func attemptOnce[T any](clients []*rpc.Client, f func(client *rpc.Client) (T, error)) (T, error) {
+	var result T
+	err := errors.New("no rpc clients available")
+	for _, client := range clients {
+		if result, err = f(client); err == nil {
+			return result, nil
+		}
+	}
+	return result, err
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/samber/lo"
+)
+
+type QueriedDelegationState struct {
+	// cycle the state was requested for, the state itself is stored under the baking power origin cycle
+	RequestedCycle int64 `json:"requested_cycle"`
+	*store.StoredDelegationState
+}
+
+type DelegationStatesPage struct {
+	States []QueriedDelegationState `json:"states"`
+	// empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// resolveCycleOrigins translates every cycle of the range to its baking power origin and returns the requested
+// cycle by origin. The offset may change with the protocol so cycles are not assumed to map at a constant offset.
+func (e *Engine) resolveCycleOrigins(ctx context.Context, fromCycle, toCycle int64) (map[int64]int64, error) {
+	collector := e.getCollector()
+	result := make(map[int64]int64, toCycle-fromCycle+1)
+	for cycle := fromCycle; cycle <= toCycle; cycle++ {
+		origin := collector.GetCycleBakingPowerOrigin(ctx, cycle)
+		if requested, ok := result[origin]; ok {
+			return nil, errors.Join(constants.ErrAmbiguousCycleRange, fmt.Errorf("cycles %d and %d", requested, cycle))
+		}
+		result[origin] = cycle
+	}
+	return result, nil
+}
+
+// QueryDelegationStates returns a page of stored states of the delegates in the requested cycle range.
+// Cycles are translated through GetCycleBakingPowerOrigin like GetDelegationState, archived cycles are not included.
+func (e *Engine) QueryDelegationStates(ctx context.Context, query store.DelegationStateQuery) (*DelegationStatesPage, error) {
+	requested, err := e.resolveCycleOrigins(ctx, query.FromCycle, query.ToCycle)
+	if err != nil {
+		return nil, err
+	}
+	query.Cycles = lo.Keys(requested)
+	slices.Sort(query.Cycles)
+	query.FromCycle = query.Cycles[0]
+	query.ToCycle = query.Cycles[len(query.Cycles)-1]
+
+	limit := query.Limit
+	query.Limit = limit + 1
+	states, err := e.store.QueryDelegationStates(&query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &DelegationStatesPage{States: make([]QueriedDelegationState, 0, len(states))}
+	if len(states) > limit {
+		states = states[:limit]
+		last := states[limit-1]
+		page.NextCursor = (&store.DelegationStateKey{Cycle: last.Cycle, Delegate: last.Delegate.Address}).Encode()
+	}
+	for i := range states {
+		page.States = append(page.States, QueriedDelegationState{
+			RequestedCycle:        requested[states[i].Cycle],
+			StoredDelegationState: &states[i],
+		})
+	}
+	return page, nil
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/snapshot"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+type RetentionReport struct {
+	Cycle    int64   `json:"cycle"`
+	Pruned   []int64 `json:"pruned"`
+	Archived []int64 `json:"archived"`
+}
+
+func archivePath(directory string, cycle int64) string {
+	return filepath.Join(directory, fmt.Sprintf("cycle-%d.tar.lz4", cycle))
+}
+
+// ApplyRetention prunes cycles older than the detailed cycles relative to cycle. Pruned cycles are
+// archived to disk and aggregated per baker before deletion if configured.
+func (e *Engine) ApplyRetention(cycle int64) (*RetentionReport, error) {
+	retention := e.retention
+	report := &RetentionReport{Cycle: cycle, Pruned: make([]int64, 0), Archived: make([]int64, 0)}
+	if retention.DetailedCycles == 0 {
+		return report, nil
+	}
+
+	cycles, err := e.store.GetDetailedCyclesBefore(cycle - int64(retention.DetailedCycles))
+	if err != nil {
+		return report, err
+	}
+
+	for _, prunedCycle := range cycles {
+		if retention.ArchiveDirectory != "" {
+			if err := e.archiveCycle(retention, prunedCycle); err != nil {
+				return report, errors.Join(fmt.Errorf("failed to archive cycle %d", prunedCycle), err)
+			}
+			report.Archived = append(report.Archived, prunedCycle)
+		}
+
+		// statistics stay available for the pruned cycle
+		if err := e.CacheStatistics(prunedCycle); err != nil {
+			return report, errors.Join(fmt.Errorf("failed to cache statistics of cycle %d", prunedCycle), err)
+		}
+
+		e.logger.Debug("pruning cycle", "cycle", prunedCycle, "keep_aggregates", retention.KeepAggregates)
+		if err := e.store.PruneCycle(prunedCycle, retention.KeepAggregates); err != nil {
+			return report, err
+		}
+		report.Pruned = append(report.Pruned, prunedCycle)
+		e.publish(Event{Kind: EventPruned, Cycle: prunedCycle})
+	}
+	return report, nil
+}
+
+// archiveCycle writes the cycle as a snapshot file, the file only appears once it is complete
+func (e *Engine) archiveCycle(retention configuration.RetentionConfiguration, cycle int64) error {
+	chainId, err := e.getCollector().GetChainId()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(retention.ArchiveDirectory, 0755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(retention.ArchiveDirectory, ".cycle-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if _, err := snapshot.Export(e.store, chainId.String(), cycle, cycle, file); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), archivePath(retention.ArchiveDirectory, cycle))
+}
+
+// getArchivedDelegationState looks the delegation state up in the archive file of the cycle, decoded archives are cached
+func (e *Engine) getArchivedDelegationState(delegate mavryk.Address, cycle int64) (*store.StoredDelegationState, error) {
+	if e.retention.ArchiveDirectory == "" {
+		return nil, constants.ErrNotFound
+	}
+	archived, err := e.archives.get(archivePath(e.retention.ArchiveDirectory, cycle), cycle)
+	if err != nil {
+		return nil, err
+	}
+	state, ok := archived.states[delegate.String()]
+	if !ok {
+		return nil, constants.ErrNotFound
+	}
+	result := *state
+	result.Archived = true
+	return &result, nil
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+type DelegationStateRevisionDiff struct {
+	Delegate     mavryk.Address                 `json:"delegate"`
+	Cycle        int64                          `json:"cycle"`
+	FromRevision int64                          `json:"from_revision"`
+	ToRevision   int64                          `json:"to_revision"`
+	Changes      []store.DelegatorBalanceChange `json:"changes"`
+}
+
+func (e *Engine) GetDelegationStateRevisions(ctx context.Context, delegate mavryk.Address, cycle int64) ([]store.DelegationStateRevision, error) {
+	cycle = e.getCollector().GetCycleBakingPowerOrigin(ctx, cycle)
+	return e.store.GetDelegationStateRevisions(delegate, cycle)
+}
+
+// DiffDelegationStateRevisions compares balances of two revisions, toRevision 0 means the current revision
+func (e *Engine) DiffDelegationStateRevisions(ctx context.Context, delegate mavryk.Address, cycle, fromRevision, toRevision int64) (*DelegationStateRevisionDiff, error) {
+	cycle = e.getCollector().GetCycleBakingPowerOrigin(ctx, cycle)
+	from, err := e.store.GetDelegationStateRevision(delegate, cycle, fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	to, err := e.store.GetDelegationStateRevision(delegate, cycle, toRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DelegationStateRevisionDiff{
+		Delegate:     delegate,
+		Cycle:        cycle,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Changes:      store.DiffDelegationStateBalances(from.Balances, to.Balances),
+	}, nil
+}
+
+// DiffDelegationStateCycles compares the delegators of the delegate between two cycles, archived cycles are not included.
+// The diff carries the requested cycles like the delegate history.
+func (e *Engine) DiffDelegationStateCycles(ctx context.Context, delegate mavryk.Address, fromCycle, toCycle int64) (*store.DelegationStateCycleDiff, error) {
+	collector := e.getCollector()
+	diff, err := e.store.DiffDelegationStateCycles(delegate, collector.GetCycleBakingPowerOrigin(ctx, fromCycle), collector.GetCycleBakingPowerOrigin(ctx, toCycle))
+	if err != nil {
+		return nil, err
+	}
+	diff.FromCycle = fromCycle
+	diff.ToCycle = toCycle
+	return diff, nil
+}
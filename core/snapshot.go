@@ -0,0 +1,39 @@
+package core
+
+import (
+	"io"
+
+	"github.com/mavryk-network/protocol-rewards/snapshot"
+)
+
+// CreateSnapshot writes a snapshot of stored delegation states of the cycle range tagged with the providers chain id
+func (e *Engine) CreateSnapshot(fromCycle, toCycle int64, w io.Writer) (*snapshot.Manifest, error) {
+	chainId, err := e.getCollector().GetChainId()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Export(e.store, chainId.String(), fromCycle, toCycle, w)
+}
+
+// ImportSnapshot merges the snapshot into the store after validating it was created on the providers chain.
+// Cycles merged before a failure are committed as well, they stay in the store.
+func (e *Engine) ImportSnapshot(r io.Reader, overwrite bool) (*snapshot.ImportResult, error) {
+	chainId, err := e.getCollector().GetChainId()
+	if err != nil {
+		return nil, err
+	}
+	result, err := snapshot.Import(e.store, chainId.String(), r, overwrite)
+	for _, cycle := range result.Cycles {
+		if _, err := e.CommitCycle(cycle); err != nil {
+			e.logger.Error("failed to commit cycle", "cycle", cycle, "error", err.Error())
+		}
+		if err := e.CacheStatistics(cycle); err != nil {
+			e.logger.Error("failed to cache cycle statistics", "cycle", cycle, "error", err.Error())
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+	e.logger.Info("imported snapshot", "chain_id", chainId.String(), "from", result.Manifest.FromCycle, "to", result.Manifest.ToCycle, "imported", result.Imported, "skipped", result.Skipped)
+	return result, nil
+}
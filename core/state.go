@@ -59,6 +59,17 @@ func (s *state) IsDelegateBeingFetched(cycle int64, delegate mavryk.Address) boo
 	return slices.Contains(s.delegatesBeingFetched[cycle], delegate)
 }
 
+func (s *state) CountDelegatesBeingFetched() int {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	count := 0
+	for _, delegates := range s.delegatesBeingFetched {
+		count += len(delegates)
+	}
+	return count
+}
+
 func (s *state) SetLastFetchedCycle(cycle int64) {
 	mtx.Lock()
 	defer mtx.Unlock()
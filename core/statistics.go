@@ -0,0 +1,48 @@
+package core
+
+import (
+	"context"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/common"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+// CacheStatistics precomputes the statistics of the completed cycle
+func (e *Engine) CacheStatistics(cycle int64) error {
+	statistics, err := e.store.CacheStatistics(cycle)
+	if err != nil {
+		return err
+	}
+	e.logger.Debug("cached cycle statistics", "cycle", cycle, "bakers", statistics.Network.Bakers)
+	return nil
+}
+
+// GetDelegateHistory returns the stake composition of the baker for cycles in range [fromCycle, toCycle].
+// Cycles are translated to the baking power origin and back, records carry the requested cycle.
+func (e *Engine) GetDelegateHistory(ctx context.Context, delegate mavryk.Address, fromCycle, toCycle int64) ([]store.DelegateCycleRecord, error) {
+	origin := e.getCollector().GetCycleBakingPowerOrigin(ctx, fromCycle)
+	offset := fromCycle - origin
+	records, err := e.store.GetDelegateHistory(delegate, origin, toCycle-offset)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		records[i].Cycle += offset
+	}
+	return records, nil
+}
+
+// GetOverstakeAnalytics evaluates the staking limit of the baker from the stored staking parameters and balances
+func (e *Engine) GetOverstakeAnalytics(ctx context.Context, delegate mavryk.Address, cycle int64) (*common.OverstakeAnalytics, error) {
+	cycle = e.getCollector().GetCycleBakingPowerOrigin(ctx, cycle)
+	state, err := e.store.GetDelegationState(delegate, cycle)
+	if err != nil {
+		return nil, err
+	}
+	if state.StakingParameters == nil {
+		return nil, constants.ErrStakingParametersNotAvailable
+	}
+	return common.AnalyzeOverstake(delegate, cycle, common.StakingParameters(*state.StakingParameters), common.DelegatedBalances(state.Balances)), nil
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/common"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/store"
+)
+
+type DelegatorMismatch struct {
+	Address  mavryk.Address            `json:"address"`
+	Stored   *common.DelegatorBalances `json:"stored"`
+	Computed *common.DelegatorBalances `json:"computed"`
+}
+
+type VerificationResult struct {
+	Delegate       mavryk.Address              `json:"delegate"`
+	Cycle          int64                       `json:"cycle"`
+	Stored         bool                        `json:"stored"`
+	StoredStatus   store.DelegationStateStatus `json:"stored_status"`
+	ComputedStatus store.DelegationStateStatus `json:"computed_status"`
+	Mismatches     []DelegatorMismatch         `json:"mismatches"`
+	Error          string                      `json:"error,omitempty"`
+}
+
+func (r *VerificationResult) IsValid() bool {
+	return r.Error == "" && r.Stored && r.StoredStatus == r.ComputedStatus && len(r.Mismatches) == 0
+}
+
+func compareDelegationStates(stored, computed *store.StoredDelegationState) []DelegatorMismatch {
+	mismatches := make([]DelegatorMismatch, 0)
+	for addr, storedBalances := range stored.Balances {
+		computedBalances, ok := computed.Balances[addr]
+		if ok && computedBalances == storedBalances {
+			continue
+		}
+		mismatch := DelegatorMismatch{Address: addr, Stored: &storedBalances}
+		if ok {
+			mismatch.Computed = &computedBalances
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	for addr, computedBalances := range computed.Balances {
+		if _, ok := stored.Balances[addr]; ok {
+			continue
+		}
+		mismatches = append(mismatches, DelegatorMismatch{Address: addr, Computed: &computedBalances})
+	}
+	return mismatches
+}
+
+// VerifyDelegateDelegationState recomputes the delegation state from the chain and compares it with the stored one.
+// Nothing is stored.
+func (e *Engine) VerifyDelegateDelegationState(ctx context.Context, delegateAddress mavryk.Address, cycle, lastBlockInTheCycle int64) (*VerificationResult, error) {
+	if lastBlockInTheCycle == 0 {
+		lastBlockInTheCycle = e.getCollector().determineLastBlockOfCycle(cycle)
+	}
+
+	result := &VerificationResult{
+		Delegate: delegateAddress,
+		Cycle:    cycle,
+	}
+
+	stored, err := e.store.GetDelegationState(delegateAddress, cycle)
+	switch {
+	case errors.Is(err, constants.ErrNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		result.Stored = true
+		result.StoredStatus = stored.Status
+	}
+
+	computed, err := e.collectDelegationState(ctx, delegateAddress, cycle, lastBlockInTheCycle, &defaultFetchOptions)
+	if err != nil {
+		return nil, err
+	}
+	result.ComputedStatus = computed.Status
+
+	if stored == nil {
+		stored = &store.StoredDelegationState{}
+	}
+	result.Mismatches = compareDelegationStates(stored, computed)
+	return result, nil
+}
+
+// VerifyCycleDelegationStates verifies delegation states of all active delegates (respecting the delegate filter) in the cycle
+func (e *Engine) VerifyCycleDelegationStates(ctx context.Context, cycle, lastBlockInTheCycle int64) ([]*VerificationResult, error) {
+	if lastBlockInTheCycle == 0 {
+		lastBlockInTheCycle = e.getCollector().determineLastBlockOfCycle(cycle)
+	}
+
+	delegates, err := e.getDelegates(ctx, lastBlockInTheCycle)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*VerificationResult, 0, len(delegates))
+	err = runInParallel(ctx, delegates, constants.DELEGATE_FETCH_BATCH_SIZE, func(ctx context.Context, item mavryk.Address, mtx *sync.RWMutex) bool {
+		result, err := e.VerifyDelegateDelegationState(ctx, item, cycle, lastBlockInTheCycle)
+		if err != nil {
+			e.logger.Error("failed to verify delegate delegation state", "cycle", cycle, "delegate", item.String(), "error", err.Error())
+			result = &VerificationResult{Delegate: item, Cycle: cycle, Error: err.Error()}
+		}
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		results = append(results, result)
+		return false
+	})
+	return results, err
+}
@@ -0,0 +1,185 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/parquet-go/parquet-go"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSONL   Format = "jsonl"
+	FormatParquet Format = "parquet"
+)
+
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case FormatCSV, FormatJSONL, FormatParquet:
+		return Format(value), nil
+	default:
+		return "", errors.Join(constants.ErrUnsupportedExportFormat, fmt.Errorf("format %q", value))
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatJSONL:
+		return "application/jsonl"
+	default:
+		return "application/vnd.apache.parquet"
+	}
+}
+
+// Row is a flat record of a single delegator of a baker in a cycle
+type Row struct {
+	Cycle             int64  `json:"cycle" parquet:"cycle"`
+	Baker             string `json:"baker" parquet:"baker,dict"`
+	Delegator         string `json:"delegator" parquet:"delegator"`
+	DelegatedBalance  int64  `json:"delegated_balance" parquet:"delegated_balance"`
+	StakedBalance     int64  `json:"staked_balance" parquet:"staked_balance"`
+	OverstakedBalance int64  `json:"overstaked_balance" parquet:"overstaked_balance"`
+	Status            string `json:"status" parquet:"status,dict"`
+}
+
+var csvHeader = []string{"cycle", "baker", "delegator", "delegated_balance", "staked_balance", "overstaked_balance", "status"}
+
+func (r *Row) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(r.Cycle, 10),
+		r.Baker,
+		r.Delegator,
+		strconv.FormatInt(r.DelegatedBalance, 10),
+		strconv.FormatInt(r.StakedBalance, 10),
+		strconv.FormatInt(r.OverstakedBalance, 10),
+		r.Status,
+	}
+}
+
+func RowFromBalance(balance *store.DelegationStateBalanceWithStatus) Row {
+	return Row{
+		Cycle:             balance.Cycle,
+		Baker:             balance.Baker.String(),
+		Delegator:         balance.Delegator.String(),
+		DelegatedBalance:  balance.DelegatedBalance,
+		StakedBalance:     balance.StakedBalance,
+		OverstakedBalance: balance.OverstakedBalance,
+		Status:            balance.Status.String(),
+	}
+}
+
+type Writer interface {
+	Write(rows ...Row) error
+	// flushes buffered rows and writes format trailer if any, does not close the underlying writer
+	Close() error
+}
+
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvHeader); err != nil {
+			return nil, err
+		}
+		return &csvWriter{writer: writer}, nil
+	case FormatJSONL:
+		return &jsonlWriter{encoder: json.NewEncoder(w)}, nil
+	case FormatParquet:
+		return &parquetWriter{writer: parquet.NewGenericWriter[Row](w,
+			parquet.MaxRowsPerRowGroup(constants.EXPORT_ROW_GROUP_SIZE),
+			parquet.Compression(&parquet.Snappy),
+		)}, nil
+	default:
+		return nil, constants.ErrUnsupportedExportFormat
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+// rows are buffered by the csv writer and only flushed on Close
+func (w *csvWriter) Write(rows ...Row) error {
+	for _, row := range rows {
+		if err := w.writer.Write(row.csvRecord()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonlWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonlWriter) Write(rows ...Row) error {
+	for _, row := range rows {
+		if err := w.encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
+
+type parquetWriter struct {
+	writer *parquet.GenericWriter[Row]
+}
+
+func (w *parquetWriter) Write(rows ...Row) error {
+	_, err := w.writer.Write(rows)
+	return err
+}
+
+func (w *parquetWriter) Close() error {
+	return w.writer.Close()
+}
+
+type Filter struct {
+	FromCycle int64
+	ToCycle   int64
+	Delegates []mavryk.Address
+}
+
+// Export streams rows of all delegation states matching the filter into w.
+// Balances are read one by one so memory does not grow with the amount of exported data.
+// Stops with the context error once ctx is done.
+func Export(ctx context.Context, s *store.Store, filter *Filter, format Format, w io.Writer) (int, error) {
+	writer, err := NewWriter(format, w)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = s.ForEachDelegationStateBalance(filter.FromCycle, filter.ToCycle, filter.Delegates, func(balance *store.DelegationStateBalanceWithStatus) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		count++
+		return writer.Write(RowFromBalance(balance))
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, writer.Close()
+}
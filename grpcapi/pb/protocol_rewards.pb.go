@@ -0,0 +1,1589 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: grpcapi/pb/protocol_rewards.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DelegationStateStatus int32
+
+const (
+	DelegationStateStatus_DELEGATION_STATE_STATUS_OK                    DelegationStateStatus = 0
+	DelegationStateStatus_DELEGATION_STATE_STATUS_MINIMUM_NOT_AVAILABLE DelegationStateStatus = 1
+)
+
+// Enum value maps for DelegationStateStatus.
+var (
+	DelegationStateStatus_name = map[int32]string{
+		0: "DELEGATION_STATE_STATUS_OK",
+		1: "DELEGATION_STATE_STATUS_MINIMUM_NOT_AVAILABLE",
+	}
+	DelegationStateStatus_value = map[string]int32{
+		"DELEGATION_STATE_STATUS_OK":                    0,
+		"DELEGATION_STATE_STATUS_MINIMUM_NOT_AVAILABLE": 1,
+	}
+)
+
+func (x DelegationStateStatus) Enum() *DelegationStateStatus {
+	p := new(DelegationStateStatus)
+	*p = x
+	return p
+}
+
+func (x DelegationStateStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DelegationStateStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_grpcapi_pb_protocol_rewards_proto_enumTypes[0].Descriptor()
+}
+
+func (DelegationStateStatus) Type() protoreflect.EnumType {
+	return &file_grpcapi_pb_protocol_rewards_proto_enumTypes[0]
+}
+
+func (x DelegationStateStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DelegationStateStatus.Descriptor instead.
+func (DelegationStateStatus) EnumDescriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{0}
+}
+
+type CycleEventKind int32
+
+const (
+	CycleEventKind_CYCLE_EVENT_KIND_UNSPECIFIED       CycleEventKind = 0
+	CycleEventKind_CYCLE_EVENT_KIND_DELEGATE_FINISHED CycleEventKind = 1
+	CycleEventKind_CYCLE_EVENT_KIND_CYCLE_FINISHED    CycleEventKind = 2
+)
+
+// Enum value maps for CycleEventKind.
+var (
+	CycleEventKind_name = map[int32]string{
+		0: "CYCLE_EVENT_KIND_UNSPECIFIED",
+		1: "CYCLE_EVENT_KIND_DELEGATE_FINISHED",
+		2: "CYCLE_EVENT_KIND_CYCLE_FINISHED",
+	}
+	CycleEventKind_value = map[string]int32{
+		"CYCLE_EVENT_KIND_UNSPECIFIED":       0,
+		"CYCLE_EVENT_KIND_DELEGATE_FINISHED": 1,
+		"CYCLE_EVENT_KIND_CYCLE_FINISHED":    2,
+	}
+)
+
+func (x CycleEventKind) Enum() *CycleEventKind {
+	p := new(CycleEventKind)
+	*p = x
+	return p
+}
+
+func (x CycleEventKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CycleEventKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_grpcapi_pb_protocol_rewards_proto_enumTypes[1].Descriptor()
+}
+
+func (CycleEventKind) Type() protoreflect.EnumType {
+	return &file_grpcapi_pb_protocol_rewards_proto_enumTypes[1]
+}
+
+func (x CycleEventKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CycleEventKind.Descriptor instead.
+func (CycleEventKind) EnumDescriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{1}
+}
+
+type DelegationStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Cycle   int64  `protobuf:"varint,2,opt,name=cycle,proto3" json:"cycle,omitempty"`
+}
+
+func (x *DelegationStateRequest) Reset() {
+	*x = DelegationStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelegationStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelegationStateRequest) ProtoMessage() {}
+
+func (x *DelegationStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelegationStateRequest.ProtoReflect.Descriptor instead.
+func (*DelegationStateRequest) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DelegationStateRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *DelegationStateRequest) GetCycle() int64 {
+	if x != nil {
+		return x.Cycle
+	}
+	return 0
+}
+
+type DelegatorBalances struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address          string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	DelegatedBalance int64  `protobuf:"varint,2,opt,name=delegated_balance,json=delegatedBalance,proto3" json:"delegated_balance,omitempty"`
+	StakedBalance    int64  `protobuf:"varint,3,opt,name=staked_balance,json=stakedBalance,proto3" json:"staked_balance,omitempty"`
+	// portion of staked balance included in delegated balance
+	OverstakedBalance int64 `protobuf:"varint,4,opt,name=overstaked_balance,json=overstakedBalance,proto3" json:"overstaked_balance,omitempty"`
+}
+
+func (x *DelegatorBalances) Reset() {
+	*x = DelegatorBalances{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelegatorBalances) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelegatorBalances) ProtoMessage() {}
+
+func (x *DelegatorBalances) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelegatorBalances.ProtoReflect.Descriptor instead.
+func (*DelegatorBalances) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DelegatorBalances) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *DelegatorBalances) GetDelegatedBalance() int64 {
+	if x != nil {
+		return x.DelegatedBalance
+	}
+	return 0
+}
+
+func (x *DelegatorBalances) GetStakedBalance() int64 {
+	if x != nil {
+		return x.StakedBalance
+	}
+	return 0
+}
+
+func (x *DelegatorBalances) GetOverstakedBalance() int64 {
+	if x != nil {
+		return x.OverstakedBalance
+	}
+	return 0
+}
+
+type DelegationState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Delegate string                `protobuf:"bytes,1,opt,name=delegate,proto3" json:"delegate,omitempty"`
+	Cycle    int64                 `protobuf:"varint,2,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	Status   DelegationStateStatus `protobuf:"varint,3,opt,name=status,proto3,enum=protocolrewards.v1.DelegationStateStatus" json:"status,omitempty"`
+	// ordered by address
+	Balances []*DelegatorBalances   `protobuf:"bytes,4,rep,name=balances,proto3" json:"balances,omitempty"`
+	Revision int64                  `protobuf:"varint,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	Reason   string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	StoredAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=stored_at,json=storedAt,proto3" json:"stored_at,omitempty"`
+}
+
+func (x *DelegationState) Reset() {
+	*x = DelegationState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelegationState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelegationState) ProtoMessage() {}
+
+func (x *DelegationState) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelegationState.ProtoReflect.Descriptor instead.
+func (*DelegationState) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DelegationState) GetDelegate() string {
+	if x != nil {
+		return x.Delegate
+	}
+	return ""
+}
+
+func (x *DelegationState) GetCycle() int64 {
+	if x != nil {
+		return x.Cycle
+	}
+	return 0
+}
+
+func (x *DelegationState) GetStatus() DelegationStateStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DelegationStateStatus_DELEGATION_STATE_STATUS_OK
+}
+
+func (x *DelegationState) GetBalances() []*DelegatorBalances {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+func (x *DelegationState) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *DelegationState) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DelegationState) GetStoredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StoredAt
+	}
+	return nil
+}
+
+type RewardsSplitDelegator struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address          string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	DelegatedBalance int64  `protobuf:"varint,2,opt,name=delegated_balance,json=delegatedBalance,proto3" json:"delegated_balance,omitempty"`
+	StakedBalance    int64  `protobuf:"varint,3,opt,name=staked_balance,json=stakedBalance,proto3" json:"staked_balance,omitempty"`
+}
+
+func (x *RewardsSplitDelegator) Reset() {
+	*x = RewardsSplitDelegator{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RewardsSplitDelegator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RewardsSplitDelegator) ProtoMessage() {}
+
+func (x *RewardsSplitDelegator) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RewardsSplitDelegator.ProtoReflect.Descriptor instead.
+func (*RewardsSplitDelegator) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RewardsSplitDelegator) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *RewardsSplitDelegator) GetDelegatedBalance() int64 {
+	if x != nil {
+		return x.DelegatedBalance
+	}
+	return 0
+}
+
+func (x *RewardsSplitDelegator) GetStakedBalance() int64 {
+	if x != nil {
+		return x.StakedBalance
+	}
+	return 0
+}
+
+type RewardsSplit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cycle                    int64 `protobuf:"varint,1,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	OwnDelegatedBalance      int64 `protobuf:"varint,2,opt,name=own_delegated_balance,json=ownDelegatedBalance,proto3" json:"own_delegated_balance,omitempty"`
+	OwnStakedBalance         int64 `protobuf:"varint,3,opt,name=own_staked_balance,json=ownStakedBalance,proto3" json:"own_staked_balance,omitempty"`
+	ExternalDelegatedBalance int64 `protobuf:"varint,4,opt,name=external_delegated_balance,json=externalDelegatedBalance,proto3" json:"external_delegated_balance,omitempty"`
+	ExternalStakedBalance    int64 `protobuf:"varint,5,opt,name=external_staked_balance,json=externalStakedBalance,proto3" json:"external_staked_balance,omitempty"`
+	DelegatorsCount          int32 `protobuf:"varint,6,opt,name=delegators_count,json=delegatorsCount,proto3" json:"delegators_count,omitempty"`
+	// ordered by address
+	Delegators []*RewardsSplitDelegator `protobuf:"bytes,7,rep,name=delegators,proto3" json:"delegators,omitempty"`
+}
+
+func (x *RewardsSplit) Reset() {
+	*x = RewardsSplit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RewardsSplit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RewardsSplit) ProtoMessage() {}
+
+func (x *RewardsSplit) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RewardsSplit.ProtoReflect.Descriptor instead.
+func (*RewardsSplit) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RewardsSplit) GetCycle() int64 {
+	if x != nil {
+		return x.Cycle
+	}
+	return 0
+}
+
+func (x *RewardsSplit) GetOwnDelegatedBalance() int64 {
+	if x != nil {
+		return x.OwnDelegatedBalance
+	}
+	return 0
+}
+
+func (x *RewardsSplit) GetOwnStakedBalance() int64 {
+	if x != nil {
+		return x.OwnStakedBalance
+	}
+	return 0
+}
+
+func (x *RewardsSplit) GetExternalDelegatedBalance() int64 {
+	if x != nil {
+		return x.ExternalDelegatedBalance
+	}
+	return 0
+}
+
+func (x *RewardsSplit) GetExternalStakedBalance() int64 {
+	if x != nil {
+		return x.ExternalStakedBalance
+	}
+	return 0
+}
+
+func (x *RewardsSplit) GetDelegatorsCount() int32 {
+	if x != nil {
+		return x.DelegatorsCount
+	}
+	return 0
+}
+
+func (x *RewardsSplit) GetDelegators() []*RewardsSplitDelegator {
+	if x != nil {
+		return x.Delegators
+	}
+	return nil
+}
+
+type StatisticsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cycle int64 `protobuf:"varint,1,opt,name=cycle,proto3" json:"cycle,omitempty"`
+}
+
+func (x *StatisticsRequest) Reset() {
+	*x = StatisticsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatisticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatisticsRequest) ProtoMessage() {}
+
+func (x *StatisticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatisticsRequest.ProtoReflect.Descriptor instead.
+func (*StatisticsRequest) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StatisticsRequest) GetCycle() int64 {
+	if x != nil {
+		return x.Cycle
+	}
+	return 0
+}
+
+// delegated plus staked balances of delegators
+type DelegatorSizeStatistics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min    int64 `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Median int64 `protobuf:"varint,2,opt,name=median,proto3" json:"median,omitempty"`
+	Max    int64 `protobuf:"varint,3,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *DelegatorSizeStatistics) Reset() {
+	*x = DelegatorSizeStatistics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelegatorSizeStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelegatorSizeStatistics) ProtoMessage() {}
+
+func (x *DelegatorSizeStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelegatorSizeStatistics.ProtoReflect.Descriptor instead.
+func (*DelegatorSizeStatistics) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DelegatorSizeStatistics) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *DelegatorSizeStatistics) GetMedian() int64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *DelegatorSizeStatistics) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+type DelegateStatistics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Delegate          string `protobuf:"bytes,1,opt,name=delegate,proto3" json:"delegate,omitempty"`
+	OwnStaked         int64  `protobuf:"varint,2,opt,name=own_staked,json=ownStaked,proto3" json:"own_staked,omitempty"`
+	OwnDelegated      int64  `protobuf:"varint,3,opt,name=own_delegated,json=ownDelegated,proto3" json:"own_delegated,omitempty"`
+	ExternalStaked    int64  `protobuf:"varint,4,opt,name=external_staked,json=externalStaked,proto3" json:"external_staked,omitempty"`
+	ExternalDelegated int64  `protobuf:"varint,5,opt,name=external_delegated,json=externalDelegated,proto3" json:"external_delegated,omitempty"`
+	// part of external staked balance counted as delegated
+	ExternalOverstaked int64                    `protobuf:"varint,6,opt,name=external_overstaked,json=externalOverstaked,proto3" json:"external_overstaked,omitempty"`
+	Delegators         int32                    `protobuf:"varint,7,opt,name=delegators,proto3" json:"delegators,omitempty"`
+	Stakers            int32                    `protobuf:"varint,8,opt,name=stakers,proto3" json:"stakers,omitempty"`
+	BakingPower        int64                    `protobuf:"varint,9,opt,name=baking_power,json=bakingPower,proto3" json:"baking_power,omitempty"`
+	NetworkShare       float64                  `protobuf:"fixed64,10,opt,name=network_share,json=networkShare,proto3" json:"network_share,omitempty"`
+	DelegatorSizes     *DelegatorSizeStatistics `protobuf:"bytes,11,opt,name=delegator_sizes,json=delegatorSizes,proto3" json:"delegator_sizes,omitempty"`
+	// empty for cycles computed from the aggregates of pruned cycles
+	Status string `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *DelegateStatistics) Reset() {
+	*x = DelegateStatistics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DelegateStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelegateStatistics) ProtoMessage() {}
+
+func (x *DelegateStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelegateStatistics.ProtoReflect.Descriptor instead.
+func (*DelegateStatistics) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DelegateStatistics) GetDelegate() string {
+	if x != nil {
+		return x.Delegate
+	}
+	return ""
+}
+
+func (x *DelegateStatistics) GetOwnStaked() int64 {
+	if x != nil {
+		return x.OwnStaked
+	}
+	return 0
+}
+
+func (x *DelegateStatistics) GetOwnDelegated() int64 {
+	if x != nil {
+		return x.OwnDelegated
+	}
+	return 0
+}
+
+func (x *DelegateStatistics) GetExternalStaked() int64 {
+	if x != nil {
+		return x.ExternalStaked
+	}
+	return 0
+}
+
+func (x *DelegateStatistics) GetExternalDelegated() int64 {
+	if x != nil {
+		return x.ExternalDelegated
+	}
+	return 0
+}
+
+func (x *DelegateStatistics) GetExternalOverstaked() int64 {
+	if x != nil {
+		return x.ExternalOverstaked
+	}
+	return 0
+}
+
+func (x *DelegateStatistics) GetDelegators() int32 {
+	if x != nil {
+		return x.Delegators
+	}
+	return 0
+}
+
+func (x *DelegateStatistics) GetStakers() int32 {
+	if x != nil {
+		return x.Stakers
+	}
+	return 0
+}
+
+func (x *DelegateStatistics) GetBakingPower() int64 {
+	if x != nil {
+		return x.BakingPower
+	}
+	return 0
+}
+
+func (x *DelegateStatistics) GetNetworkShare() float64 {
+	if x != nil {
+		return x.NetworkShare
+	}
+	return 0
+}
+
+func (x *DelegateStatistics) GetDelegatorSizes() *DelegatorSizeStatistics {
+	if x != nil {
+		return x.DelegatorSizes
+	}
+	return nil
+}
+
+func (x *DelegateStatistics) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// delegators and stakers are counted once
+type NetworkStatistics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bakers         int32                    `protobuf:"varint,1,opt,name=bakers,proto3" json:"bakers,omitempty"`
+	Delegators     int32                    `protobuf:"varint,2,opt,name=delegators,proto3" json:"delegators,omitempty"`
+	Stakers        int32                    `protobuf:"varint,3,opt,name=stakers,proto3" json:"stakers,omitempty"`
+	Staked         int64                    `protobuf:"varint,4,opt,name=staked,proto3" json:"staked,omitempty"`
+	Delegated      int64                    `protobuf:"varint,5,opt,name=delegated,proto3" json:"delegated,omitempty"`
+	Overstaked     int64                    `protobuf:"varint,6,opt,name=overstaked,proto3" json:"overstaked,omitempty"`
+	BakingPower    int64                    `protobuf:"varint,7,opt,name=baking_power,json=bakingPower,proto3" json:"baking_power,omitempty"`
+	DelegatorSizes *DelegatorSizeStatistics `protobuf:"bytes,8,opt,name=delegator_sizes,json=delegatorSizes,proto3" json:"delegator_sizes,omitempty"`
+	Statuses       map[string]int32         `protobuf:"bytes,9,rep,name=statuses,proto3" json:"statuses,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *NetworkStatistics) Reset() {
+	*x = NetworkStatistics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkStatistics) ProtoMessage() {}
+
+func (x *NetworkStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkStatistics.ProtoReflect.Descriptor instead.
+func (*NetworkStatistics) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *NetworkStatistics) GetBakers() int32 {
+	if x != nil {
+		return x.Bakers
+	}
+	return 0
+}
+
+func (x *NetworkStatistics) GetDelegators() int32 {
+	if x != nil {
+		return x.Delegators
+	}
+	return 0
+}
+
+func (x *NetworkStatistics) GetStakers() int32 {
+	if x != nil {
+		return x.Stakers
+	}
+	return 0
+}
+
+func (x *NetworkStatistics) GetStaked() int64 {
+	if x != nil {
+		return x.Staked
+	}
+	return 0
+}
+
+func (x *NetworkStatistics) GetDelegated() int64 {
+	if x != nil {
+		return x.Delegated
+	}
+	return 0
+}
+
+func (x *NetworkStatistics) GetOverstaked() int64 {
+	if x != nil {
+		return x.Overstaked
+	}
+	return 0
+}
+
+func (x *NetworkStatistics) GetBakingPower() int64 {
+	if x != nil {
+		return x.BakingPower
+	}
+	return 0
+}
+
+func (x *NetworkStatistics) GetDelegatorSizes() *DelegatorSizeStatistics {
+	if x != nil {
+		return x.DelegatorSizes
+	}
+	return nil
+}
+
+func (x *NetworkStatistics) GetStatuses() map[string]int32 {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+type CycleStatistics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cycle int64 `protobuf:"varint,1,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	// ordered by delegate
+	Delegates []*DelegateStatistics `protobuf:"bytes,2,rep,name=delegates,proto3" json:"delegates,omitempty"`
+	Network   *NetworkStatistics    `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+}
+
+func (x *CycleStatistics) Reset() {
+	*x = CycleStatistics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CycleStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CycleStatistics) ProtoMessage() {}
+
+func (x *CycleStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CycleStatistics.ProtoReflect.Descriptor instead.
+func (*CycleStatistics) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CycleStatistics) GetCycle() int64 {
+	if x != nil {
+		return x.Cycle
+	}
+	return 0
+}
+
+func (x *CycleStatistics) GetDelegates() []*DelegateStatistics {
+	if x != nil {
+		return x.Delegates
+	}
+	return nil
+}
+
+func (x *CycleStatistics) GetNetwork() *NetworkStatistics {
+	if x != nil {
+		return x.Network
+	}
+	return nil
+}
+
+type Availability struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Available bool `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`
+}
+
+func (x *Availability) Reset() {
+	*x = Availability{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Availability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Availability) ProtoMessage() {}
+
+func (x *Availability) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Availability.ProtoReflect.Descriptor instead.
+func (*Availability) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Availability) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+type WatchCyclesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// only delegate events of these delegates are sent, cycle events are always sent
+	Delegates []string `protobuf:"bytes,1,rep,name=delegates,proto3" json:"delegates,omitempty"`
+}
+
+func (x *WatchCyclesRequest) Reset() {
+	*x = WatchCyclesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchCyclesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCyclesRequest) ProtoMessage() {}
+
+func (x *WatchCyclesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCyclesRequest.ProtoReflect.Descriptor instead.
+func (*WatchCyclesRequest) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchCyclesRequest) GetDelegates() []string {
+	if x != nil {
+		return x.Delegates
+	}
+	return nil
+}
+
+type CycleEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind  CycleEventKind `protobuf:"varint,1,opt,name=kind,proto3,enum=protocolrewards.v1.CycleEventKind" json:"kind,omitempty"`
+	Cycle int64          `protobuf:"varint,2,opt,name=cycle,proto3" json:"cycle,omitempty"`
+	// set for delegate events only, error is empty if the delegate was stored
+	Delegate string `protobuf:"bytes,3,opt,name=delegate,proto3" json:"delegate,omitempty"`
+	Error    string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// set for cycle events only
+	Delegates int32                  `protobuf:"varint,5,opt,name=delegates,proto3" json:"delegates,omitempty"`
+	Failures  int32                  `protobuf:"varint,6,opt,name=failures,proto3" json:"failures,omitempty"`
+	Time      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *CycleEvent) Reset() {
+	*x = CycleEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CycleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CycleEvent) ProtoMessage() {}
+
+func (x *CycleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_grpcapi_pb_protocol_rewards_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CycleEvent.ProtoReflect.Descriptor instead.
+func (*CycleEvent) Descriptor() ([]byte, []int) {
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CycleEvent) GetKind() CycleEventKind {
+	if x != nil {
+		return x.Kind
+	}
+	return CycleEventKind_CYCLE_EVENT_KIND_UNSPECIFIED
+}
+
+func (x *CycleEvent) GetCycle() int64 {
+	if x != nil {
+		return x.Cycle
+	}
+	return 0
+}
+
+func (x *CycleEvent) GetDelegate() string {
+	if x != nil {
+		return x.Delegate
+	}
+	return ""
+}
+
+func (x *CycleEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CycleEvent) GetDelegates() int32 {
+	if x != nil {
+		return x.Delegates
+	}
+	return 0
+}
+
+func (x *CycleEvent) GetFailures() int32 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *CycleEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+var File_grpcapi_pb_protocol_rewards_proto protoreflect.FileDescriptor
+
+var file_grpcapi_pb_protocol_rewards_proto_rawDesc = []byte{
+	0x0a, 0x21, 0x67, 0x72, 0x70, 0x63, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x62, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x12, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77,
+	0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x48, 0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65,
+	0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x79, 0x63, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x79, 0x63,
+	0x6c, 0x65, 0x22, 0xb0, 0x01, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72,
+	0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x64,
+	0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12,
+	0x25, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x42,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x2d, 0x0a, 0x12, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x74,
+	0x61, 0x6b, 0x65, 0x64, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x11, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x22, 0xb6, 0x02, 0x0a, 0x0f, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x6c,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x6c,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x41, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x29, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x41,
+	0x0a, 0x08, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x42,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x08, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x37, 0x0a, 0x09, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x41, 0x74, 0x22, 0x85,
+	0x01, 0x0a, 0x15, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x44,
+	0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x64,
+	0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12,
+	0x25, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x42,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x22, 0xf2, 0x02, 0x0a, 0x0c, 0x52, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x73, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x79, 0x63, 0x6c, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x32, 0x0a,
+	0x15, 0x6f, 0x77, 0x6e, 0x5f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x62,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x6f, 0x77,
+	0x6e, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x12, 0x2c, 0x0a, 0x12, 0x6f, 0x77, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x5f,
+	0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6f,
+	0x77, 0x6e, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12,
+	0x3c, 0x0a, 0x1a, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x64, 0x65, 0x6c, 0x65,
+	0x67, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x18, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x44, 0x65, 0x6c,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x36, 0x0a,
+	0x17, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64,
+	0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15,
+	0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74,
+	0x6f, 0x72, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x49, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x07,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72,
+	0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64,
+	0x73, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x52,
+	0x0a, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x22, 0x29, 0x0a, 0x11, 0x53,
+	0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x22, 0x55, 0x0a, 0x17, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61,
+	0x74, 0x6f, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63,
+	0x73, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03,
+	0x6d, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d,
+	0x61, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x22, 0xed, 0x03,
+	0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73,
+	0x74, 0x69, 0x63, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x77, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6f, 0x77, 0x6e, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x12,
+	0x23, 0x0a, 0x0d, 0x6f, 0x77, 0x6e, 0x5f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6f, 0x77, 0x6e, 0x44, 0x65, 0x6c, 0x65, 0x67,
+	0x61, 0x74, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x5f, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x65,
+	0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x12, 0x2d, 0x0a,
+	0x12, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61,
+	0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x65, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x12, 0x2f, 0x0a, 0x13,
+	0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x74, 0x61,
+	0x6b, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x65, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x4f, 0x76, 0x65, 0x72, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x12, 0x1e, 0x0a,
+	0x0a, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07,
+	0x73, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x61, 0x6b, 0x69, 0x6e,
+	0x67, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x62,
+	0x61, 0x6b, 0x69, 0x6e, 0x67, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0c, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12,
+	0x54, 0x0a, 0x0f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69,
+	0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x0e, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72,
+	0x53, 0x69, 0x7a, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xc2, 0x03,
+	0x0a, 0x11, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74,
+	0x69, 0x63, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x61, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x06, 0x62, 0x61, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x64,
+	0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0a, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x74, 0x61, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73, 0x74,
+	0x61, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x12, 0x1c, 0x0a,
+	0x09, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x6f,
+	0x76, 0x65, 0x72, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x62,
+	0x61, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0b, 0x62, 0x61, 0x6b, 0x69, 0x6e, 0x67, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x54,
+	0x0a, 0x0f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73,
+	0x74, 0x69, 0x63, 0x73, 0x52, 0x0e, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x53,
+	0x69, 0x7a, 0x65, 0x73, 0x12, 0x4f, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73,
+	0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x65, 0x73, 0x1a, 0x3b, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0xae, 0x01, 0x0a, 0x0f, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x12, 0x44, 0x0a, 0x09,
+	0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74,
+	0x65, 0x73, 0x12, 0x3f, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65,
+	0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x22, 0x2c, 0x0a, 0x0c, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c,
+	0x65, 0x22, 0x32, 0x0a, 0x12, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x67,
+	0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65,
+	0x67, 0x61, 0x74, 0x65, 0x73, 0x22, 0xf6, 0x01, 0x0a, 0x0a, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77,
+	0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x79, 0x63, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x79, 0x63,
+	0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74,
+	0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x2e,
+	0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x2a, 0x6a,
+	0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x1a, 0x44, 0x45, 0x4c, 0x45, 0x47,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x31, 0x0a, 0x2d, 0x44, 0x45, 0x4c, 0x45, 0x47,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x4d, 0x49, 0x4e, 0x49, 0x4d, 0x55, 0x4d, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x41,
+	0x56, 0x41, 0x49, 0x4c, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x01, 0x2a, 0x7f, 0x0a, 0x0e, 0x43, 0x79,
+	0x63, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x20, 0x0a, 0x1c,
+	0x43, 0x59, 0x43, 0x4c, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x26,
+	0x0a, 0x22, 0x43, 0x59, 0x43, 0x4c, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49,
+	0x4e, 0x44, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x47, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x49, 0x4e, 0x49,
+	0x53, 0x48, 0x45, 0x44, 0x10, 0x01, 0x12, 0x23, 0x0a, 0x1f, 0x43, 0x59, 0x43, 0x4c, 0x45, 0x5f,
+	0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x43, 0x59, 0x43, 0x4c, 0x45,
+	0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x02, 0x32, 0xec, 0x03, 0x0a, 0x0f,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x12,
+	0x65, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x67,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61,
+	0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x5f, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x52, 0x65, 0x77,
+	0x61, 0x72, 0x64, 0x73, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x12, 0x2a, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x73, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x12, 0x5b, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73,
+	0x74, 0x69, 0x63, 0x73, 0x12, 0x5b, 0x0a, 0x0b, 0x49, 0x73, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61,
+	0x62, 0x6c, 0x65, 0x12, 0x2a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65,
+	0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x12, 0x57, 0x0a, 0x0b, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43, 0x79, 0x63, 0x6c, 0x65, 0x73,
+	0x12, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x43, 0x79, 0x63, 0x6c, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x79,
+	0x63, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x37, 0x5a, 0x35, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x61, 0x76, 0x72, 0x79, 0x6b, 0x2d,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x2d, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x61, 0x70, 0x69,
+	0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_grpcapi_pb_protocol_rewards_proto_rawDescOnce sync.Once
+	file_grpcapi_pb_protocol_rewards_proto_rawDescData = file_grpcapi_pb_protocol_rewards_proto_rawDesc
+)
+
+func file_grpcapi_pb_protocol_rewards_proto_rawDescGZIP() []byte {
+	file_grpcapi_pb_protocol_rewards_proto_rawDescOnce.Do(func() {
+		file_grpcapi_pb_protocol_rewards_proto_rawDescData = protoimpl.X.CompressGZIP(file_grpcapi_pb_protocol_rewards_proto_rawDescData)
+	})
+	return file_grpcapi_pb_protocol_rewards_proto_rawDescData
+}
+
+var file_grpcapi_pb_protocol_rewards_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_grpcapi_pb_protocol_rewards_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_grpcapi_pb_protocol_rewards_proto_goTypes = []any{
+	(DelegationStateStatus)(0),      // 0: protocolrewards.v1.DelegationStateStatus
+	(CycleEventKind)(0),             // 1: protocolrewards.v1.CycleEventKind
+	(*DelegationStateRequest)(nil),  // 2: protocolrewards.v1.DelegationStateRequest
+	(*DelegatorBalances)(nil),       // 3: protocolrewards.v1.DelegatorBalances
+	(*DelegationState)(nil),         // 4: protocolrewards.v1.DelegationState
+	(*RewardsSplitDelegator)(nil),   // 5: protocolrewards.v1.RewardsSplitDelegator
+	(*RewardsSplit)(nil),            // 6: protocolrewards.v1.RewardsSplit
+	(*StatisticsRequest)(nil),       // 7: protocolrewards.v1.StatisticsRequest
+	(*DelegatorSizeStatistics)(nil), // 8: protocolrewards.v1.DelegatorSizeStatistics
+	(*DelegateStatistics)(nil),      // 9: protocolrewards.v1.DelegateStatistics
+	(*NetworkStatistics)(nil),       // 10: protocolrewards.v1.NetworkStatistics
+	(*CycleStatistics)(nil),         // 11: protocolrewards.v1.CycleStatistics
+	(*Availability)(nil),            // 12: protocolrewards.v1.Availability
+	(*WatchCyclesRequest)(nil),      // 13: protocolrewards.v1.WatchCyclesRequest
+	(*CycleEvent)(nil),              // 14: protocolrewards.v1.CycleEvent
+	nil,                             // 15: protocolrewards.v1.NetworkStatistics.StatusesEntry
+	(*timestamppb.Timestamp)(nil),   // 16: google.protobuf.Timestamp
+}
+var file_grpcapi_pb_protocol_rewards_proto_depIdxs = []int32{
+	0,  // 0: protocolrewards.v1.DelegationState.status:type_name -> protocolrewards.v1.DelegationStateStatus
+	3,  // 1: protocolrewards.v1.DelegationState.balances:type_name -> protocolrewards.v1.DelegatorBalances
+	16, // 2: protocolrewards.v1.DelegationState.stored_at:type_name -> google.protobuf.Timestamp
+	5,  // 3: protocolrewards.v1.RewardsSplit.delegators:type_name -> protocolrewards.v1.RewardsSplitDelegator
+	8,  // 4: protocolrewards.v1.DelegateStatistics.delegator_sizes:type_name -> protocolrewards.v1.DelegatorSizeStatistics
+	8,  // 5: protocolrewards.v1.NetworkStatistics.delegator_sizes:type_name -> protocolrewards.v1.DelegatorSizeStatistics
+	15, // 6: protocolrewards.v1.NetworkStatistics.statuses:type_name -> protocolrewards.v1.NetworkStatistics.StatusesEntry
+	9,  // 7: protocolrewards.v1.CycleStatistics.delegates:type_name -> protocolrewards.v1.DelegateStatistics
+	10, // 8: protocolrewards.v1.CycleStatistics.network:type_name -> protocolrewards.v1.NetworkStatistics
+	1,  // 9: protocolrewards.v1.CycleEvent.kind:type_name -> protocolrewards.v1.CycleEventKind
+	16, // 10: protocolrewards.v1.CycleEvent.time:type_name -> google.protobuf.Timestamp
+	2,  // 11: protocolrewards.v1.ProtocolRewards.GetDelegationState:input_type -> protocolrewards.v1.DelegationStateRequest
+	2,  // 12: protocolrewards.v1.ProtocolRewards.GetRewardsSplit:input_type -> protocolrewards.v1.DelegationStateRequest
+	7,  // 13: protocolrewards.v1.ProtocolRewards.GetStatistics:input_type -> protocolrewards.v1.StatisticsRequest
+	2,  // 14: protocolrewards.v1.ProtocolRewards.IsAvailable:input_type -> protocolrewards.v1.DelegationStateRequest
+	13, // 15: protocolrewards.v1.ProtocolRewards.WatchCycles:input_type -> protocolrewards.v1.WatchCyclesRequest
+	4,  // 16: protocolrewards.v1.ProtocolRewards.GetDelegationState:output_type -> protocolrewards.v1.DelegationState
+	6,  // 17: protocolrewards.v1.ProtocolRewards.GetRewardsSplit:output_type -> protocolrewards.v1.RewardsSplit
+	11, // 18: protocolrewards.v1.ProtocolRewards.GetStatistics:output_type -> protocolrewards.v1.CycleStatistics
+	12, // 19: protocolrewards.v1.ProtocolRewards.IsAvailable:output_type -> protocolrewards.v1.Availability
+	14, // 20: protocolrewards.v1.ProtocolRewards.WatchCycles:output_type -> protocolrewards.v1.CycleEvent
+	16, // [16:21] is the sub-list for method output_type
+	11, // [11:16] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_grpcapi_pb_protocol_rewards_proto_init() }
+func file_grpcapi_pb_protocol_rewards_proto_init() {
+	if File_grpcapi_pb_protocol_rewards_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*DelegationStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*DelegatorBalances); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*DelegationState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*RewardsSplitDelegator); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*RewardsSplit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*StatisticsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*DelegatorSizeStatistics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*DelegateStatistics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*NetworkStatistics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*CycleStatistics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*Availability); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchCyclesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_grpcapi_pb_protocol_rewards_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*CycleEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_grpcapi_pb_protocol_rewards_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_grpcapi_pb_protocol_rewards_proto_goTypes,
+		DependencyIndexes: file_grpcapi_pb_protocol_rewards_proto_depIdxs,
+		EnumInfos:         file_grpcapi_pb_protocol_rewards_proto_enumTypes,
+		MessageInfos:      file_grpcapi_pb_protocol_rewards_proto_msgTypes,
+	}.Build()
+	File_grpcapi_pb_protocol_rewards_proto = out.File
+	file_grpcapi_pb_protocol_rewards_proto_rawDesc = nil
+	file_grpcapi_pb_protocol_rewards_proto_goTypes = nil
+	file_grpcapi_pb_protocol_rewards_proto_depIdxs = nil
+}
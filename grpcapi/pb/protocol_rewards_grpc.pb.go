@@ -0,0 +1,291 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: grpcapi/pb/protocol_rewards.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ProtocolRewards_GetDelegationState_FullMethodName = "/protocolrewards.v1.ProtocolRewards/GetDelegationState"
+	ProtocolRewards_GetRewardsSplit_FullMethodName    = "/protocolrewards.v1.ProtocolRewards/GetRewardsSplit"
+	ProtocolRewards_GetStatistics_FullMethodName      = "/protocolrewards.v1.ProtocolRewards/GetStatistics"
+	ProtocolRewards_IsAvailable_FullMethodName        = "/protocolrewards.v1.ProtocolRewards/IsAvailable"
+	ProtocolRewards_WatchCycles_FullMethodName        = "/protocolrewards.v1.ProtocolRewards/WatchCycles"
+)
+
+// ProtocolRewardsClient is the client API for ProtocolRewards service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ProtocolRewards mirrors the public http api
+type ProtocolRewardsClient interface {
+	// GetDelegationState is /delegate/:cycle/:address
+	GetDelegationState(ctx context.Context, in *DelegationStateRequest, opts ...grpc.CallOption) (*DelegationState, error)
+	// GetRewardsSplit is /v1/rewards/split/:address/:cycle, FAILED_PRECONDITION if the minimum of the cycle is not available
+	GetRewardsSplit(ctx context.Context, in *DelegationStateRequest, opts ...grpc.CallOption) (*RewardsSplit, error)
+	// GetStatistics is /statistics/:cycle
+	GetStatistics(ctx context.Context, in *StatisticsRequest, opts ...grpc.CallOption) (*CycleStatistics, error)
+	// IsAvailable is /delegate/:cycle/:address/available
+	IsAvailable(ctx context.Context, in *DelegationStateRequest, opts ...grpc.CallOption) (*Availability, error)
+	// WatchCycles streams an event whenever a delegate or a whole cycle is fetched
+	WatchCycles(ctx context.Context, in *WatchCyclesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CycleEvent], error)
+}
+
+type protocolRewardsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProtocolRewardsClient(cc grpc.ClientConnInterface) ProtocolRewardsClient {
+	return &protocolRewardsClient{cc}
+}
+
+func (c *protocolRewardsClient) GetDelegationState(ctx context.Context, in *DelegationStateRequest, opts ...grpc.CallOption) (*DelegationState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DelegationState)
+	err := c.cc.Invoke(ctx, ProtocolRewards_GetDelegationState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocolRewardsClient) GetRewardsSplit(ctx context.Context, in *DelegationStateRequest, opts ...grpc.CallOption) (*RewardsSplit, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RewardsSplit)
+	err := c.cc.Invoke(ctx, ProtocolRewards_GetRewardsSplit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocolRewardsClient) GetStatistics(ctx context.Context, in *StatisticsRequest, opts ...grpc.CallOption) (*CycleStatistics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CycleStatistics)
+	err := c.cc.Invoke(ctx, ProtocolRewards_GetStatistics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocolRewardsClient) IsAvailable(ctx context.Context, in *DelegationStateRequest, opts ...grpc.CallOption) (*Availability, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Availability)
+	err := c.cc.Invoke(ctx, ProtocolRewards_IsAvailable_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocolRewardsClient) WatchCycles(ctx context.Context, in *WatchCyclesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CycleEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProtocolRewards_ServiceDesc.Streams[0], ProtocolRewards_WatchCycles_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchCyclesRequest, CycleEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProtocolRewards_WatchCyclesClient = grpc.ServerStreamingClient[CycleEvent]
+
+// ProtocolRewardsServer is the server API for ProtocolRewards service.
+// All implementations must embed UnimplementedProtocolRewardsServer
+// for forward compatibility.
+//
+// ProtocolRewards mirrors the public http api
+type ProtocolRewardsServer interface {
+	// GetDelegationState is /delegate/:cycle/:address
+	GetDelegationState(context.Context, *DelegationStateRequest) (*DelegationState, error)
+	// GetRewardsSplit is /v1/rewards/split/:address/:cycle, FAILED_PRECONDITION if the minimum of the cycle is not available
+	GetRewardsSplit(context.Context, *DelegationStateRequest) (*RewardsSplit, error)
+	// GetStatistics is /statistics/:cycle
+	GetStatistics(context.Context, *StatisticsRequest) (*CycleStatistics, error)
+	// IsAvailable is /delegate/:cycle/:address/available
+	IsAvailable(context.Context, *DelegationStateRequest) (*Availability, error)
+	// WatchCycles streams an event whenever a delegate or a whole cycle is fetched
+	WatchCycles(*WatchCyclesRequest, grpc.ServerStreamingServer[CycleEvent]) error
+	mustEmbedUnimplementedProtocolRewardsServer()
+}
+
+// UnimplementedProtocolRewardsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedProtocolRewardsServer struct{}
+
+func (UnimplementedProtocolRewardsServer) GetDelegationState(context.Context, *DelegationStateRequest) (*DelegationState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDelegationState not implemented")
+}
+func (UnimplementedProtocolRewardsServer) GetRewardsSplit(context.Context, *DelegationStateRequest) (*RewardsSplit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRewardsSplit not implemented")
+}
+func (UnimplementedProtocolRewardsServer) GetStatistics(context.Context, *StatisticsRequest) (*CycleStatistics, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatistics not implemented")
+}
+func (UnimplementedProtocolRewardsServer) IsAvailable(context.Context, *DelegationStateRequest) (*Availability, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsAvailable not implemented")
+}
+func (UnimplementedProtocolRewardsServer) WatchCycles(*WatchCyclesRequest, grpc.ServerStreamingServer[CycleEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCycles not implemented")
+}
+func (UnimplementedProtocolRewardsServer) mustEmbedUnimplementedProtocolRewardsServer() {}
+func (UnimplementedProtocolRewardsServer) testEmbeddedByValue()                         {}
+
+// UnsafeProtocolRewardsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProtocolRewardsServer will
+// result in compilation errors.
+type UnsafeProtocolRewardsServer interface {
+	mustEmbedUnimplementedProtocolRewardsServer()
+}
+
+func RegisterProtocolRewardsServer(s grpc.ServiceRegistrar, srv ProtocolRewardsServer) {
+	// If the following call pancis, it indicates UnimplementedProtocolRewardsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ProtocolRewards_ServiceDesc, srv)
+}
+
+func _ProtocolRewards_GetDelegationState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelegationStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProtocolRewardsServer).GetDelegationState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProtocolRewards_GetDelegationState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProtocolRewardsServer).GetDelegationState(ctx, req.(*DelegationStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProtocolRewards_GetRewardsSplit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelegationStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProtocolRewardsServer).GetRewardsSplit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProtocolRewards_GetRewardsSplit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProtocolRewardsServer).GetRewardsSplit(ctx, req.(*DelegationStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProtocolRewards_GetStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatisticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProtocolRewardsServer).GetStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProtocolRewards_GetStatistics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProtocolRewardsServer).GetStatistics(ctx, req.(*StatisticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProtocolRewards_IsAvailable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelegationStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProtocolRewardsServer).IsAvailable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProtocolRewards_IsAvailable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProtocolRewardsServer).IsAvailable(ctx, req.(*DelegationStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProtocolRewards_WatchCycles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCyclesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProtocolRewardsServer).WatchCycles(m, &grpc.GenericServerStream[WatchCyclesRequest, CycleEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProtocolRewards_WatchCyclesServer = grpc.ServerStreamingServer[CycleEvent]
+
+// ProtocolRewards_ServiceDesc is the grpc.ServiceDesc for ProtocolRewards service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProtocolRewards_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "protocolrewards.v1.ProtocolRewards",
+	HandlerType: (*ProtocolRewardsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDelegationState",
+			Handler:    _ProtocolRewards_GetDelegationState_Handler,
+		},
+		{
+			MethodName: "GetRewardsSplit",
+			Handler:    _ProtocolRewards_GetRewardsSplit_Handler,
+		},
+		{
+			MethodName: "GetStatistics",
+			Handler:    _ProtocolRewards_GetStatistics_Handler,
+		},
+		{
+			MethodName: "IsAvailable",
+			Handler:    _ProtocolRewards_IsAvailable_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCycles",
+			Handler:       _ProtocolRewards_WatchCycles_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "grpcapi/pb/protocol_rewards.proto",
+}
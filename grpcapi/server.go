@@ -0,0 +1,254 @@
+// Package grpcapi serves the public api over gRPC for pipelines which prefer it over http polling
+package grpcapi
+
+//go:generate protoc -I ../ --go_out=../ --go_opt=paths=source_relative --go-grpc_out=../ --go-grpc_opt=paths=source_relative grpcapi/pb/protocol_rewards.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/common"
+	"github.com/mavryk-network/protocol-rewards/configuration"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/grpcapi/pb"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Engine is the part of core.Engine the service needs
+type Engine interface {
+	GetDelegationState(ctx context.Context, delegate mavryk.Address, cycle int64) (*store.StoredDelegationState, error)
+	IsDelegationStateAvailable(ctx context.Context, delegate mavryk.Address, cycle int64) (bool, error)
+	Statisticts(ctx context.Context, cycle int64) (*common.CycleStatistics, error)
+	SubscribeEvents() (<-chan core.Event, func())
+}
+
+type server struct {
+	pb.UnimplementedProtocolRewardsServer
+	engine Engine
+}
+
+func NewServer(engine Engine) *grpc.Server {
+	s := grpc.NewServer()
+	pb.RegisterProtocolRewardsServer(s, &server{engine: engine})
+	return s
+}
+
+// CreateGrpcApi starts the gRPC server if a listen address is configured, nil is returned otherwise
+func CreateGrpcApi(config *configuration.Runtime, engine Engine) (*grpc.Server, error) {
+	if config.GrpcListen == "" {
+		return nil, nil
+	}
+	listener, err := net.Listen("tcp", config.GrpcListen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start grpc api: %w", err)
+	}
+
+	s := NewServer(engine)
+	go func() {
+		if err := s.Serve(listener); err != nil {
+			slog.Error("grpc api stopped", "error", err.Error())
+		}
+	}()
+	return s, nil
+}
+
+func toStatusError(err error) error {
+	if errors.Is(err, constants.ErrNotFound) {
+		return status.Error(codes.NotFound, "Delegation state not found")
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func parseRequest(req *pb.DelegationStateRequest) (mavryk.Address, error) {
+	address, err := mavryk.ParseAddress(req.GetAddress())
+	if err != nil {
+		return address, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return address, nil
+}
+
+func (s *server) GetDelegationState(ctx context.Context, req *pb.DelegationStateRequest) (*pb.DelegationState, error) {
+	address, err := parseRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.engine.GetDelegationState(ctx, address, req.GetCycle())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	balances := make([]*pb.DelegatorBalances, 0, len(state.Balances))
+	for addr, balance := range state.Balances {
+		balances = append(balances, &pb.DelegatorBalances{
+			Address:           addr.String(),
+			DelegatedBalance:  balance.DelegatedBalance,
+			StakedBalance:     balance.StakedBalance,
+			OverstakedBalance: balance.OverstakedBalance,
+		})
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Address < balances[j].Address })
+
+	return &pb.DelegationState{
+		Delegate: state.Delegate.String(),
+		Cycle:    state.Cycle,
+		Status:   pb.DelegationStateStatus(state.Status),
+		Balances: balances,
+		Revision: state.Revision,
+		Reason:   string(state.Reason),
+		StoredAt: timestamppb.New(state.StoredAt),
+	}, nil
+}
+
+func (s *server) GetRewardsSplit(ctx context.Context, req *pb.DelegationStateRequest) (*pb.RewardsSplit, error) {
+	address, err := parseRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.engine.GetDelegationState(ctx, address, req.GetCycle())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if state.Status == store.DelegationStateStatusMinimumNotAvailable {
+		return nil, status.Error(codes.FailedPrecondition, "relevant minimum does not exist")
+	}
+
+	split := state.ToMvktState()
+	return &pb.RewardsSplit{
+		Cycle:                    split.Cycle,
+		OwnDelegatedBalance:      split.OwnDelegatedBalance,
+		OwnStakedBalance:         split.OwnStakedBalance,
+		ExternalDelegatedBalance: split.ExternalDelegatedBalance,
+		ExternalStakedBalance:    split.ExternalStakedBalance,
+		DelegatorsCount:          int32(split.DelegatorsCount),
+		Delegators: lo.Map(split.Delegators, func(d store.MvktDelegator, _ int) *pb.RewardsSplitDelegator {
+			return &pb.RewardsSplitDelegator{
+				Address:          d.Address.String(),
+				DelegatedBalance: d.DelegatedBalance,
+				StakedBalance:    d.StakedBalance,
+			}
+		}),
+	}, nil
+}
+
+func (s *server) GetStatistics(ctx context.Context, req *pb.StatisticsRequest) (*pb.CycleStatistics, error) {
+	statistics, err := s.engine.Statisticts(ctx, req.GetCycle())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	delegates := make([]*pb.DelegateStatistics, 0, len(statistics.Delegates))
+	for addr, delegate := range statistics.Delegates {
+		delegates = append(delegates, &pb.DelegateStatistics{
+			Delegate:           addr.String(),
+			OwnStaked:          delegate.OwnStaked,
+			OwnDelegated:       delegate.OwnDelegated,
+			ExternalStaked:     delegate.ExternalStaked,
+			ExternalDelegated:  delegate.ExternalDelegated,
+			ExternalOverstaked: delegate.ExternalOverstaked,
+			Delegators:         int32(delegate.Delegators),
+			Stakers:            int32(delegate.Stakers),
+			BakingPower:        delegate.BakingPower,
+			NetworkShare:       delegate.NetworkShare,
+			DelegatorSizes:     toDelegatorSizes(delegate.DelegatorSizes),
+			Status:             delegate.Status,
+		})
+	}
+	sort.Slice(delegates, func(i, j int) bool { return delegates[i].Delegate < delegates[j].Delegate })
+
+	network := statistics.Network
+	return &pb.CycleStatistics{
+		Cycle:     statistics.Cycle,
+		Delegates: delegates,
+		Network: &pb.NetworkStatistics{
+			Bakers:         int32(network.Bakers),
+			Delegators:     int32(network.Delegators),
+			Stakers:        int32(network.Stakers),
+			Staked:         network.Staked,
+			Delegated:      network.Delegated,
+			Overstaked:     network.Overstaked,
+			BakingPower:    network.BakingPower,
+			DelegatorSizes: toDelegatorSizes(network.DelegatorSizes),
+			Statuses:       lo.MapValues(network.Statuses, func(count int, _ string) int32 { return int32(count) }),
+		},
+	}, nil
+}
+
+func toDelegatorSizes(sizes common.DelegatorSizeStatistics) *pb.DelegatorSizeStatistics {
+	return &pb.DelegatorSizeStatistics{Min: sizes.Min, Median: sizes.Median, Max: sizes.Max}
+}
+
+func (s *server) IsAvailable(ctx context.Context, req *pb.DelegationStateRequest) (*pb.Availability, error) {
+	address, err := parseRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	available, err := s.engine.IsDelegationStateAvailable(ctx, address, req.GetCycle())
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &pb.Availability{Available: available}, nil
+}
+
+// toCycleEvent converts finished delegates and cycles, other events are not part of the stream
+func toCycleEvent(event *core.Event) (*pb.CycleEvent, bool) {
+	result := &pb.CycleEvent{
+		Cycle: event.Cycle,
+		Error: event.Error,
+		Time:  timestamppb.New(event.Time),
+	}
+	switch event.Kind {
+	case core.EventDelegateStored, core.EventDelegateFailed:
+		result.Kind = pb.CycleEventKind_CYCLE_EVENT_KIND_DELEGATE_FINISHED
+		result.Delegate = event.Delegate.String()
+	case core.EventCycleCompleted:
+		result.Kind = pb.CycleEventKind_CYCLE_EVENT_KIND_CYCLE_FINISHED
+		result.Delegates = int32(event.Delegates)
+		result.Failures = int32(event.Failures)
+	default:
+		return nil, false
+	}
+	return result, true
+}
+
+func (s *server) WatchCycles(req *pb.WatchCyclesRequest, stream pb.ProtocolRewards_WatchCyclesServer) error {
+	filter := make(core.EventFilter, 0, len(req.GetDelegates()))
+	for _, delegate := range req.GetDelegates() {
+		address, err := mavryk.ParseAddress(strings.TrimSpace(delegate))
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		filter = append(filter, address)
+	}
+
+	events, cancel := s.engine.SubscribeEvents()
+	defer cancel()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			result, ok := toCycleEvent(&event)
+			if !ok || !filter.Match(&event) {
+				continue
+			}
+			if err := stream.Send(result); err != nil {
+				return err
+			}
+		}
+	}
+}
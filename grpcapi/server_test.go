@@ -0,0 +1,180 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/common"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/core"
+	"github.com/mavryk-network/protocol-rewards/grpcapi/pb"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var (
+	baker     = mavryk.MustParseAddress("mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL")
+	delegator = mavryk.MustParseAddress("mv1CtCq3D2RrCxx6VL5aMTkfq8tYLSK4sXmN")
+	other     = mavryk.MustParseAddress("mv1SZS8SZB5Wt5GTMnLxqxn13pAXrSNMsXQ1")
+)
+
+type testEngine struct {
+	states map[int64]*store.StoredDelegationState
+	events chan core.Event
+}
+
+func (e *testEngine) GetDelegationState(ctx context.Context, delegate mavryk.Address, cycle int64) (*store.StoredDelegationState, error) {
+	state, ok := e.states[cycle]
+	if !ok || !state.Delegate.Equal(delegate) {
+		return nil, constants.ErrNotFound
+	}
+	return state, nil
+}
+
+func (e *testEngine) IsDelegationStateAvailable(ctx context.Context, delegate mavryk.Address, cycle int64) (bool, error) {
+	_, err := e.GetDelegationState(ctx, delegate, cycle)
+	return err == nil, nil
+}
+
+func (e *testEngine) Statisticts(ctx context.Context, cycle int64) (*common.CycleStatistics, error) {
+	statistics := common.NewCycleStatistics(cycle)
+	statistics.Add(baker, common.DelegateCycleStatistics{
+		OwnStaked:          1,
+		ExternalDelegated:  2,
+		ExternalOverstaked: 1,
+		Delegators:         1,
+		BakingPower:        3,
+		DelegatorSizes:     common.DelegatorSizeStatistics{Min: 2, Median: 2, Max: 2},
+		Status:             "ok",
+	})
+	statistics.Network.Delegators = 1
+	statistics.ComputeNetworkShares()
+	return statistics, nil
+}
+
+func (e *testEngine) SubscribeEvents() (<-chan core.Event, func()) {
+	return e.events, func() {}
+}
+
+func newTestClient(t *testing.T, engine Engine) pb.ProtocolRewardsClient {
+	listener := bufconn.Listen(1024 * 1024)
+	s := NewServer(engine)
+	go s.Serve(listener)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewProtocolRewardsClient(conn)
+}
+
+func newTestEngine() *testEngine {
+	return &testEngine{
+		states: map[int64]*store.StoredDelegationState{
+			10: {
+				Delegate: store.Address{Address: baker},
+				Cycle:    10,
+				Balances: store.DelegationStateBalances{
+					baker:     {DelegatedBalance: 100, StakedBalance: 50},
+					delegator: {DelegatedBalance: 30, StakedBalance: 20, OverstakedBalance: 5},
+				},
+				Revision: 1,
+			},
+			11: {
+				Delegate: store.Address{Address: baker},
+				Cycle:    11,
+				Status:   store.DelegationStateStatusMinimumNotAvailable,
+			},
+		},
+		events: make(chan core.Event, 8),
+	}
+}
+
+func TestDelegationState(t *testing.T) {
+	client := newTestClient(t, newTestEngine())
+	ctx := context.Background()
+
+	state, err := client.GetDelegationState(ctx, &pb.DelegationStateRequest{Address: baker.String(), Cycle: 10})
+	require.NoError(t, err)
+	assert.Equal(t, baker.String(), state.Delegate)
+	assert.Len(t, state.Balances, 2)
+
+	available, err := client.IsAvailable(ctx, &pb.DelegationStateRequest{Address: baker.String(), Cycle: 12})
+	require.NoError(t, err)
+	assert.False(t, available.Available)
+
+	_, err = client.GetDelegationState(ctx, &pb.DelegationStateRequest{Address: baker.String(), Cycle: 12})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetDelegationState(ctx, &pb.DelegationStateRequest{Address: "mv", Cycle: 10})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRewardsSplit(t *testing.T) {
+	client := newTestClient(t, newTestEngine())
+	ctx := context.Background()
+
+	split, err := client.GetRewardsSplit(ctx, &pb.DelegationStateRequest{Address: baker.String(), Cycle: 10})
+	require.NoError(t, err)
+	require.Len(t, split.Delegators, 1)
+	assert.Equal(t, int64(35), split.Delegators[0].DelegatedBalance)
+	assert.Equal(t, int64(15), split.Delegators[0].StakedBalance)
+
+	_, err = client.GetRewardsSplit(ctx, &pb.DelegationStateRequest{Address: baker.String(), Cycle: 11})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestStatistics(t *testing.T) {
+	client := newTestClient(t, newTestEngine())
+
+	statistics, err := client.GetStatistics(context.Background(), &pb.StatisticsRequest{Cycle: 10})
+	require.NoError(t, err)
+	require.Len(t, statistics.Delegates, 1)
+	delegate := statistics.Delegates[0]
+	assert.Equal(t, baker.String(), delegate.Delegate)
+	assert.Equal(t, int64(1), delegate.ExternalOverstaked)
+	assert.Equal(t, int64(3), delegate.BakingPower)
+	assert.Equal(t, 1.0, delegate.NetworkShare)
+	assert.Equal(t, int64(2), delegate.DelegatorSizes.GetMedian())
+	assert.Equal(t, "ok", delegate.Status)
+	assert.Equal(t, int32(1), statistics.Network.GetBakers())
+	assert.Equal(t, int64(3), statistics.Network.GetBakingPower())
+	assert.Equal(t, map[string]int32{"ok": 1}, statistics.Network.GetStatuses())
+}
+
+func TestWatchCycles(t *testing.T) {
+	engine := newTestEngine()
+	client := newTestClient(t, engine)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchCycles(ctx, &pb.WatchCyclesRequest{Delegates: []string{baker.String()}})
+	require.NoError(t, err)
+
+	engine.events <- core.Event{Kind: core.EventDelegateStored, Cycle: 10, Delegate: &other}
+	engine.events <- core.Event{Kind: core.EventDelegateStarted, Cycle: 10, Delegate: &baker}
+	engine.events <- core.Event{Kind: core.EventDelegateStored, Cycle: 10, Delegate: &baker}
+	engine.events <- core.Event{Kind: core.EventPruned, Cycle: 1}
+	engine.events <- core.Event{Kind: core.EventCycleCompleted, Cycle: 10, Delegates: 2, Failures: 1}
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.CycleEventKind_CYCLE_EVENT_KIND_DELEGATE_FINISHED, event.Kind)
+	assert.Equal(t, baker.String(), event.Delegate)
+
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.CycleEventKind_CYCLE_EVENT_KIND_CYCLE_FINISHED, event.Kind)
+	assert.Equal(t, int32(1), event.Failures)
+}
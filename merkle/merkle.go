@@ -0,0 +1,154 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// domain separation so a leaf can never be taken for an inner node
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+type Hash [32]byte
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText accepts exactly the hex encoding of a hash, proofs are parsed from untrusted input
+func (h *Hash) UnmarshalText(data []byte) error {
+	if len(data) != 2*len(h) {
+		return fmt.Errorf("invalid hash length %d, expected %d hex characters", len(data), 2*len(h))
+	}
+	_, err := hex.Decode(h[:], data)
+	return err
+}
+
+// Leaf is the committed balance of a delegator (or the baker itself) with a baker in a cycle
+type Leaf struct {
+	Cycle             int64  `json:"cycle"`
+	Baker             string `json:"baker"`
+	Delegator         string `json:"delegator"`
+	DelegatedBalance  int64  `json:"delegated_balance"`
+	StakedBalance     int64  `json:"staked_balance"`
+	OverstakedBalance int64  `json:"overstaked_balance"`
+}
+
+func (l *Leaf) Hash() Hash {
+	encoded, _ := json.Marshal(l) // fixed field order, can not fail
+	return blake2b.Sum256(append([]byte{leafPrefix}, encoded...))
+}
+
+func hashNodes(left, right Hash) Hash {
+	data := make([]byte, 0, 1+2*len(left))
+	data = append(data, nodePrefix)
+	data = append(data, left[:]...)
+	data = append(data, right[:]...)
+	return blake2b.Sum256(data)
+}
+
+// SortLeaves orders leaves by baker and delegator, the order the tree is built in
+func SortLeaves(leaves []Leaf) {
+	sort.Slice(leaves, func(i, j int) bool {
+		if leaves[i].Baker != leaves[j].Baker {
+			return leaves[i].Baker < leaves[j].Baker
+		}
+		return leaves[i].Delegator < leaves[j].Delegator
+	})
+}
+
+type Tree struct {
+	// levels[0] are leaf hashes, the last level holds the root
+	levels [][]Hash
+}
+
+// Build builds the tree over leaves in the given order. A node without sibling is promoted to the next level as is.
+func Build(leaves []Leaf) *Tree {
+	level := make([]Hash, len(leaves))
+	for i := range leaves {
+		level[i] = leaves[i].Hash()
+	}
+	tree := &Tree{levels: [][]Hash{level}}
+	for len(level) > 1 {
+		next := make([]Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, hashNodes(level[i], level[i+1]))
+		}
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+	return tree
+}
+
+// Root of an empty tree is the zero hash
+func (t *Tree) Root() Hash {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		return Hash{}
+	}
+	return top[0]
+}
+
+func (t *Tree) Leaves() int {
+	return len(t.levels[0])
+}
+
+type ProofStep struct {
+	Sibling Hash `json:"sibling"`
+	// sibling is the left operand when hashing
+	Left bool `json:"left"`
+}
+
+// Proof returns the siblings from the leaf at index up to the root
+func (t *Tree) Proof(index int) []ProofStep {
+	proof := make([]ProofStep, 0)
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, ProofStep{Sibling: level[sibling], Left: sibling < index})
+		}
+		index /= 2
+	}
+	return proof
+}
+
+// Verify checks the leaf is included in the tree with the root
+func Verify(root Hash, leaf Leaf, proof []ProofStep) bool {
+	hash := leaf.Hash()
+	for _, step := range proof {
+		if step.Left {
+			hash = hashNodes(step.Sibling, hash)
+		} else {
+			hash = hashNodes(hash, step.Sibling)
+		}
+	}
+	return bytes.Equal(hash[:], root[:])
+}
+
+// InclusionProof is everything a delegator needs to check its balance against a published root
+type InclusionProof struct {
+	Root   Hash        `json:"root"`
+	Leaf   Leaf        `json:"leaf"`
+	Index  int         `json:"index"`
+	Leaves int         `json:"leaves"`
+	Proof  []ProofStep `json:"proof"`
+}
+
+func (p *InclusionProof) Verify(root Hash) bool {
+	return p.Root == root && Verify(root, p.Leaf, p.Proof)
+}
@@ -0,0 +1,72 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLeaves(count int) []Leaf {
+	leaves := make([]Leaf, 0, count)
+	for i := 0; i < count; i++ {
+		leaves = append(leaves, Leaf{
+			Cycle:            745,
+			Baker:            "mv1baker",
+			Delegator:        fmt.Sprintf("mv1delegator%03d", i),
+			DelegatedBalance: int64(i * 1000),
+			StakedBalance:    int64(i),
+		})
+	}
+	return leaves
+}
+
+func TestProofs(t *testing.T) {
+	assert := assert.New(t)
+
+	for count := 1; count <= 17; count++ {
+		leaves := testLeaves(count)
+		tree := Build(leaves)
+		assert.Equal(count, tree.Leaves())
+		for i, leaf := range leaves {
+			assert.True(Verify(tree.Root(), leaf, tree.Proof(i)), "count %d index %d", count, i)
+		}
+	}
+}
+
+func TestTampering(t *testing.T) {
+	assert := assert.New(t)
+
+	leaves := testLeaves(7)
+	tree := Build(leaves)
+	proof := tree.Proof(3)
+
+	tampered := leaves[3]
+	tampered.DelegatedBalance++
+	assert.False(Verify(tree.Root(), tampered, proof))
+	assert.False(Verify(tree.Root(), leaves[4], proof))
+
+	proof[0].Left = !proof[0].Left
+	assert.False(Verify(tree.Root(), leaves[3], proof))
+}
+
+func TestRootDependsOnOrder(t *testing.T) {
+	leaves := testLeaves(4)
+	root := Build(leaves).Root()
+	leaves[0], leaves[1] = leaves[1], leaves[0]
+	assert.NotEqual(t, root, Build(leaves).Root())
+
+	SortLeaves(leaves)
+	assert.Equal(t, root, Build(leaves).Root())
+}
+
+func TestHashUnmarshalText(t *testing.T) {
+	hash := testLeaves(1)[0].Hash()
+	var parsed Hash
+	assert.Nil(t, parsed.UnmarshalText([]byte(hash.String())))
+	assert.Equal(t, hash, parsed)
+
+	assert.Error(t, parsed.UnmarshalText([]byte(hash.String()+"00")))
+	assert.Error(t, parsed.UnmarshalText([]byte(hash.String()[:62])))
+	assert.Error(t, parsed.UnmarshalText(nil))
+}
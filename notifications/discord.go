@@ -96,6 +96,9 @@ func (dn *DiscordNotificator) notify(msg string) error {
 }
 
 func Notify(notificator *DiscordNotificator, msg string) {
+	if notificator == nil {
+		return
+	}
 	slog.Debug("sending discord notification")
 
 	if err := notificator.notify(msg); err != nil {
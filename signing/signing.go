@@ -0,0 +1,197 @@
+package signing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	HeaderSignature = "X-Signature"
+	HeaderSigner    = "X-Signer"
+	HeaderPublicKey = "X-Signer-Key"
+	HeaderSubject   = "X-Signed-Subject"
+)
+
+// Subject is the delegate and requested cycle a response was signed for, it is signed with the payload
+// so the response can not be passed off as the answer for another delegate or cycle
+type Subject struct {
+	Delegate string `json:"delegate"`
+	Cycle    int64  `json:"cycle"`
+}
+
+func NewSubject(delegate mavryk.Address, cycle int64) *Subject {
+	return &Subject{Delegate: delegate.String(), Cycle: cycle}
+}
+
+// String is the header encoding <delegate>/<cycle>
+func (s *Subject) String() string {
+	return fmt.Sprintf("%s/%d", s.Delegate, s.Cycle)
+}
+
+func ParseSubject(value string) (*Subject, error) {
+	delegate, cycle, ok := strings.Cut(value, "/")
+	if !ok {
+		return nil, errors.Join(constants.ErrInvalidSignature, fmt.Errorf("invalid subject %q", value))
+	}
+	parsedCycle, err := strconv.ParseInt(cycle, 10, 64)
+	if err != nil {
+		return nil, errors.Join(constants.ErrInvalidSignature, err)
+	}
+	return &Subject{Delegate: delegate, Cycle: parsedCycle}, nil
+}
+
+// Envelope carries the signed canonical payload together with the signature
+type Envelope struct {
+	Payload   json.RawMessage `json:"payload"`
+	Subject   *Subject        `json:"subject,omitempty"`
+	Signature string          `json:"signature"`
+	Signer    string          `json:"signer"`
+	PublicKey string          `json:"public_key"`
+}
+
+// signedMessage is the payload, together with the subject if there is one
+func signedMessage(payload json.RawMessage, subject *Subject) ([]byte, error) {
+	if subject == nil {
+		return payload, nil
+	}
+	return Canonicalize(map[string]any{"payload": payload, "subject": subject})
+}
+
+// Canonicalize encodes v as json with object keys sorted and without insignificant whitespace.
+// Numbers are kept as they are so balances do not lose precision.
+func Canonicalize(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(generic); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// Digest is the blake2b-256 hash of the payload which is signed
+func Digest(payload []byte) []byte {
+	digest := blake2b.Sum256(payload)
+	return digest[:]
+}
+
+type Signer struct {
+	key     mavryk.PrivateKey
+	public  mavryk.Key
+	address mavryk.Address
+}
+
+// NewSigner creates a signer from an ed25519 (edsk) private key
+func NewSigner(privateKey string) (*Signer, error) {
+	key, err := mavryk.ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, errors.Join(constants.ErrInvalidSigningKey, err)
+	}
+	address := key.Address()
+	if !strings.HasPrefix(address.String(), "mv1") {
+		return nil, errors.Join(constants.ErrInvalidSigningKey, fmt.Errorf("expected ed25519 key, got signer %s", address.String()))
+	}
+	return &Signer{
+		key:     key,
+		public:  key.Public(),
+		address: address,
+	}, nil
+}
+
+func (s *Signer) Address() mavryk.Address {
+	return s.address
+}
+
+// Sign canonicalizes v and signs its digest, bound to the subject if it is set
+func (s *Signer) Sign(v any, subject *Subject) (*Envelope, error) {
+	payload, err := Canonicalize(v)
+	if err != nil {
+		return nil, err
+	}
+	message, err := signedMessage(payload, subject)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := s.key.Sign(Digest(message))
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		Payload:   payload,
+		Subject:   subject,
+		Signature: signature.String(),
+		Signer:    s.address.String(),
+		PublicKey: s.public.String(),
+	}, nil
+}
+
+// Verify checks the signature of the payload was made by the pinned signer. If subject is set
+// the envelope has to be signed for it, otherwise any subject signed with the payload is accepted.
+func (e *Envelope) Verify(signer mavryk.Address, subject *Subject) error {
+	if subject != nil && (e.Subject == nil || *e.Subject != *subject) {
+		return errors.Join(constants.ErrInvalidSignature, fmt.Errorf("signed for %v, expected %s", e.Subject, subject.String()))
+	}
+	key, err := mavryk.ParseKey(e.PublicKey)
+	if err != nil {
+		return errors.Join(constants.ErrInvalidSignature, err)
+	}
+	if !key.Address().Equal(signer) {
+		return errors.Join(constants.ErrInvalidSignature, fmt.Errorf("signed by %s, expected %s", key.Address().String(), signer.String()))
+	}
+	if e.Signer != "" && e.Signer != signer.String() {
+		return errors.Join(constants.ErrInvalidSignature, fmt.Errorf("signer %s does not match the public key", e.Signer))
+	}
+	signature, err := mavryk.ParseSignature(e.Signature)
+	if err != nil {
+		return errors.Join(constants.ErrInvalidSignature, err)
+	}
+	message, err := signedMessage(e.Payload, e.Subject)
+	if err != nil {
+		return errors.Join(constants.ErrInvalidSignature, err)
+	}
+	if err := key.Verify(Digest(message), signature); err != nil {
+		return errors.Join(constants.ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+// EnvelopeFromResponse builds the envelope from the signature headers and the received body
+func EnvelopeFromResponse(header http.Header, body []byte) (*Envelope, error) {
+	if header.Get(HeaderSignature) == "" || header.Get(HeaderPublicKey) == "" {
+		return nil, errors.Join(constants.ErrInvalidSignature, errors.New("response is not signed"))
+	}
+	var subject *Subject
+	if value := header.Get(HeaderSubject); value != "" {
+		var err error
+		if subject, err = ParseSubject(value); err != nil {
+			return nil, err
+		}
+	}
+	return &Envelope{
+		Payload:   body,
+		Subject:   subject,
+		Signature: header.Get(HeaderSignature),
+		Signer:    header.Get(HeaderSigner),
+		PublicKey: header.Get(HeaderPublicKey),
+	}, nil
+}
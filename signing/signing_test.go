@@ -0,0 +1,84 @@
+package signing
+
+import (
+	"testing"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize(t *testing.T) {
+	assert := assert.New(t)
+
+	a, err := Canonicalize(map[string]any{"b": 1, "a": []int{2, 1}, "c": map[string]int64{"y": 1, "x": 9007199254740993}})
+	assert.Nil(err)
+	assert.Equal(`{"a":[2,1],"b":1,"c":{"x":9007199254740993,"y":1}}`, string(a))
+
+	type value struct {
+		Zeta  string `json:"zeta"`
+		Alpha string `json:"alpha"`
+	}
+	b, err := Canonicalize(&value{Zeta: "<z>", Alpha: "a"})
+	assert.Nil(err)
+	assert.Equal(`{"alpha":"a","zeta":"<z>"}`, string(b))
+
+	assert.Len(Digest(b), 32)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := mavryk.GenerateKey(mavryk.KeyTypeEd25519)
+	assert.Nil(err)
+	signer, err := NewSigner(key.String())
+	assert.Nil(err)
+
+	envelope, err := signer.Sign(map[string]any{"cycle": 745, "delegate": "mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL"}, nil)
+	assert.Nil(err)
+	assert.Nil(envelope.Verify(signer.Address(), nil))
+
+	tampered := *envelope
+	tampered.Payload = []byte(`{"cycle":746,"delegate":"mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL"}`)
+	assert.ErrorIs(tampered.Verify(signer.Address(), nil), constants.ErrInvalidSignature)
+
+	other, err := mavryk.GenerateKey(mavryk.KeyTypeEd25519)
+	assert.Nil(err)
+	assert.ErrorIs(envelope.Verify(other.Address(), nil), constants.ErrInvalidSignature)
+
+	// the envelope carries the other key but the signature was made by signer
+	swapped := *envelope
+	swapped.Signer = ""
+	swapped.PublicKey = other.Public().String()
+	assert.ErrorIs(swapped.Verify(other.Address(), nil), constants.ErrInvalidSignature)
+}
+
+func TestSubjectIsSigned(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := mavryk.GenerateKey(mavryk.KeyTypeEd25519)
+	assert.Nil(err)
+	signer, err := NewSigner(key.String())
+	assert.Nil(err)
+	delegate, err := mavryk.ParseAddress("mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL")
+	assert.Nil(err)
+
+	subject := NewSubject(delegate, 745)
+	envelope, err := signer.Sign(map[string]any{"cycle": 744}, subject)
+	assert.Nil(err)
+	assert.Nil(envelope.Verify(signer.Address(), subject))
+	assert.Nil(envelope.Verify(signer.Address(), nil))
+
+	parsed, err := ParseSubject(subject.String())
+	assert.Nil(err)
+	assert.Equal(subject, parsed)
+
+	// the payload can not be passed off as the answer for another cycle
+	assert.ErrorIs(envelope.Verify(signer.Address(), NewSubject(delegate, 746)), constants.ErrInvalidSignature)
+	replayed := *envelope
+	replayed.Subject = NewSubject(delegate, 746)
+	assert.ErrorIs(replayed.Verify(signer.Address(), replayed.Subject), constants.ErrInvalidSignature)
+	stripped := *envelope
+	stripped.Subject = nil
+	assert.ErrorIs(stripped.Verify(signer.Address(), nil), constants.ErrInvalidSignature)
+}
@@ -0,0 +1,314 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/pierrec/lz4/v4"
+)
+
+const (
+	manifestName = "manifest.json"
+	cyclesDir    = "cycles"
+)
+
+type CycleEntry struct {
+	Cycle  int64  `json:"cycle"`
+	States int    `json:"states"`
+	Sha256 string `json:"sha256"`
+}
+
+// Manifest describes the content of a snapshot, it is the first entry of the archive
+type Manifest struct {
+	FormatVersion int          `json:"format_version"`
+	SchemaVersion int          `json:"schema_version"`
+	ChainId       string       `json:"chain_id"`
+	FromCycle     int64        `json:"from_cycle"`
+	ToCycle       int64        `json:"to_cycle"`
+	CreatedAt     time.Time    `json:"created_at"`
+	Cycles        []CycleEntry `json:"cycles"`
+}
+
+func (m *Manifest) getCycle(cycle int64) *CycleEntry {
+	for i := range m.Cycles {
+		if m.Cycles[i].Cycle == cycle {
+			return &m.Cycles[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks the snapshot can be imported into an instance running on the chain
+func (m *Manifest) Validate(chainId string) error {
+	if m.FormatVersion != constants.SNAPSHOT_FORMAT_VERSION {
+		return errors.Join(constants.ErrInvalidSnapshot, fmt.Errorf("unsupported format version %d", m.FormatVersion))
+	}
+	if m.SchemaVersion > store.LatestSchemaVersion() {
+		return errors.Join(constants.ErrSnapshotSchemaNotSupported, fmt.Errorf("snapshot schema version %d, supported up to %d", m.SchemaVersion, store.LatestSchemaVersion()))
+	}
+	if m.ChainId != chainId {
+		return errors.Join(constants.ErrSnapshotChainMismatch, fmt.Errorf("snapshot chain %s, providers chain %s", m.ChainId, chainId))
+	}
+	return nil
+}
+
+func cycleEntryName(cycle int64) string {
+	return path.Join(cyclesDir, fmt.Sprintf("%d.jsonl", cycle))
+}
+
+type cyclePayload struct {
+	entry  CycleEntry
+	offset int64
+	size   int64
+}
+
+// Write writes an lz4 compressed tar snapshot of the states yielded by source into w.
+// Source has to yield states ordered by cycle. Cycle payloads are staged in a temporary file
+// so the manifest with checksums can be written as the first entry.
+func Write(w io.Writer, manifest *Manifest, source func(f func(state *store.StoredDelegationState) error) error) (*Manifest, error) {
+	staging, err := os.CreateTemp("", "snapshot-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(staging.Name())
+	defer staging.Close()
+
+	payloads := make([]cyclePayload, 0)
+	var current *cyclePayload
+	var offset int64
+	hash := sha256.New()
+	stagingWriter := bufio.NewWriter(staging)
+	counter := &countingWriter{}
+	encoder := json.NewEncoder(io.MultiWriter(stagingWriter, hash, counter))
+
+	finishCycle := func() {
+		if current == nil {
+			return
+		}
+		current.entry.Sha256 = hex.EncodeToString(hash.Sum(nil))
+		current.size = counter.n - current.offset
+		payloads = append(payloads, *current)
+		offset = counter.n
+		hash.Reset()
+	}
+
+	err = source(func(state *store.StoredDelegationState) error {
+		if current == nil || current.entry.Cycle != state.Cycle {
+			finishCycle()
+			current = &cyclePayload{entry: CycleEntry{Cycle: state.Cycle}, offset: offset}
+		}
+		current.entry.States++
+		return encoder.Encode(state)
+	})
+	if err != nil {
+		return nil, err
+	}
+	finishCycle()
+	if err := stagingWriter.Flush(); err != nil {
+		return nil, err
+	}
+
+	result := *manifest
+	result.FormatVersion = constants.SNAPSHOT_FORMAT_VERSION
+	result.SchemaVersion = store.LatestSchemaVersion()
+	result.Cycles = make([]CycleEntry, 0, len(payloads))
+	for _, payload := range payloads {
+		result.Cycles = append(result.Cycles, payload.entry)
+	}
+	manifestData, err := json.MarshalIndent(&result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	compressed := lz4.NewWriter(w)
+	archive := tar.NewWriter(compressed)
+	if err := writeEntry(archive, manifestName, result.CreatedAt, int64(len(manifestData)), bytes.NewReader(manifestData)); err != nil {
+		return nil, err
+	}
+	for _, payload := range payloads {
+		reader := io.NewSectionReader(staging, payload.offset, payload.size)
+		if err := writeEntry(archive, cycleEntryName(payload.entry.Cycle), result.CreatedAt, payload.size, reader); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	if err := compressed.Close(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+func writeEntry(archive *tar.Writer, name string, modTime time.Time, size int64, r io.Reader) error {
+	if err := archive.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    size,
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := io.Copy(archive, r)
+	return err
+}
+
+// Read reads a snapshot from r. The manifest is passed to validate before any cycle is read,
+// then states of every cycle are passed to apply once their checksum is verified.
+func Read(r io.Reader, validate func(manifest *Manifest) error, apply func(cycle int64, states []*store.StoredDelegationState) error) (*Manifest, error) {
+	archive := tar.NewReader(lz4.NewReader(r))
+
+	header, err := archive.Next()
+	if err != nil {
+		return nil, errors.Join(constants.ErrInvalidSnapshot, err)
+	}
+	if header.Name != manifestName {
+		return nil, errors.Join(constants.ErrInvalidSnapshot, fmt.Errorf("expected %s as first entry, got %s", manifestName, header.Name))
+	}
+	var manifest Manifest
+	if err := json.NewDecoder(archive).Decode(&manifest); err != nil {
+		return nil, errors.Join(constants.ErrInvalidSnapshot, err)
+	}
+	if err := validate(&manifest); err != nil {
+		return &manifest, err
+	}
+
+	seen := make(map[int64]bool, len(manifest.Cycles))
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return &manifest, errors.Join(constants.ErrInvalidSnapshot, err)
+		}
+
+		cycle, err := parseCycleEntryName(header.Name)
+		if err != nil {
+			return &manifest, err
+		}
+		entry := manifest.getCycle(cycle)
+		if entry == nil {
+			return &manifest, errors.Join(constants.ErrInvalidSnapshot, fmt.Errorf("cycle %d is not listed in the manifest", cycle))
+		}
+
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return &manifest, errors.Join(constants.ErrInvalidSnapshot, err)
+		}
+		states, err := decodeCycle(data, entry)
+		if err != nil {
+			return &manifest, err
+		}
+		if err := apply(cycle, states); err != nil {
+			return &manifest, err
+		}
+		seen[cycle] = true
+	}
+
+	for _, entry := range manifest.Cycles {
+		if !seen[entry.Cycle] {
+			return &manifest, errors.Join(constants.ErrInvalidSnapshot, fmt.Errorf("cycle %d is missing in the archive", entry.Cycle))
+		}
+	}
+	return &manifest, nil
+}
+
+func parseCycleEntryName(name string) (int64, error) {
+	dir, file := path.Split(name)
+	if strings.TrimSuffix(dir, "/") != cyclesDir || !strings.HasSuffix(file, ".jsonl") {
+		return 0, errors.Join(constants.ErrInvalidSnapshot, fmt.Errorf("unexpected entry %s", name))
+	}
+	cycle, err := strconv.ParseInt(strings.TrimSuffix(file, ".jsonl"), 10, 64)
+	if err != nil {
+		return 0, errors.Join(constants.ErrInvalidSnapshot, fmt.Errorf("unexpected entry %s", name))
+	}
+	return cycle, nil
+}
+
+func decodeCycle(data []byte, entry *CycleEntry) ([]*store.StoredDelegationState, error) {
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != entry.Sha256 {
+		return nil, errors.Join(constants.ErrSnapshotChecksumMismatch, fmt.Errorf("cycle %d", entry.Cycle))
+	}
+
+	states := make([]*store.StoredDelegationState, 0, entry.States)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var state store.StoredDelegationState
+		if err := decoder.Decode(&state); err != nil {
+			return nil, errors.Join(constants.ErrInvalidSnapshot, err)
+		}
+		if state.Cycle != entry.Cycle {
+			return nil, errors.Join(constants.ErrInvalidSnapshot, fmt.Errorf("state of cycle %d found in cycle %d", state.Cycle, entry.Cycle))
+		}
+		states = append(states, &state)
+	}
+	if len(states) != entry.States {
+		return nil, errors.Join(constants.ErrInvalidSnapshot, fmt.Errorf("cycle %d has %d states, manifest lists %d", entry.Cycle, len(states), entry.States))
+	}
+	return states, nil
+}
+
+// Export writes a snapshot of all stored delegation states in the cycle range
+func Export(s *store.Store, chainId string, fromCycle, toCycle int64, w io.Writer) (*Manifest, error) {
+	return Write(w, &Manifest{
+		ChainId:   chainId,
+		FromCycle: fromCycle,
+		ToCycle:   toCycle,
+		CreatedAt: time.Now().UTC(),
+	}, func(f func(state *store.StoredDelegationState) error) error {
+		return s.ForEachDelegationState(fromCycle, toCycle, nil, f)
+	})
+}
+
+type ImportResult struct {
+	Manifest *Manifest `json:"manifest"`
+	Imported int64     `json:"imported"`
+	Skipped  int64     `json:"skipped"`
+	// cycles merged into the store, cycles read before a failure stay merged
+	Cycles []int64 `json:"cycles"`
+}
+
+// Import merges the snapshot into the store. Existing states are kept unless overwrite is set,
+// so importing the same snapshot repeatedly yields the same result.
+func Import(s *store.Store, chainId string, r io.Reader, overwrite bool) (*ImportResult, error) {
+	result := &ImportResult{}
+	manifest, err := Read(r, func(manifest *Manifest) error {
+		return manifest.Validate(chainId)
+	}, func(cycle int64, states []*store.StoredDelegationState) error {
+		imported, err := s.MergeDelegationStates(states, overwrite)
+		if err != nil {
+			return err
+		}
+		result.Imported += imported
+		result.Skipped += int64(len(states)) - imported
+		result.Cycles = append(result.Cycles, cycle)
+		return nil
+	})
+	result.Manifest = manifest
+	return result, err
+}
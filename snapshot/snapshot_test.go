@@ -0,0 +1,85 @@
+package snapshot
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/common"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/mavryk-network/protocol-rewards/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStates() []*store.StoredDelegationState {
+	baker := mavryk.MustParseAddress("mv1ELYevTeKz1tb8J8cqtYnz2vRdv9tamNmr")
+	delegator := mavryk.MustParseAddress("mv1VNRtHZdLzSJfyvvz2cxAoR1kWoNDWMisL")
+
+	result := make([]*store.StoredDelegationState, 0)
+	for cycle := int64(745); cycle <= 746; cycle++ {
+		result = append(result, &store.StoredDelegationState{
+			Delegate: store.Address{Address: baker},
+			Cycle:    cycle,
+			Status:   store.DelegationStateStatusOk,
+			Balances: store.DelegationStateBalances{
+				baker:     common.DelegatorBalances{DelegatedBalance: 1000, StakedBalance: 100},
+				delegator: common.DelegatorBalances{DelegatedBalance: cycle},
+			},
+		})
+	}
+	return result
+}
+
+func writeTestSnapshot(t *testing.T, states []*store.StoredDelegationState) (*Manifest, []byte) {
+	buffer := bytes.NewBuffer(nil)
+	manifest, err := Write(buffer, &Manifest{ChainId: "NetXtest", FromCycle: 745, ToCycle: 746, CreatedAt: time.Now().UTC()}, func(f func(state *store.StoredDelegationState) error) error {
+		for _, state := range states {
+			if err := f(state); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	return manifest, buffer.Bytes()
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	states := testStates()
+	manifest, data := writeTestSnapshot(t, states)
+	assert.Equal(constants.SNAPSHOT_FORMAT_VERSION, manifest.FormatVersion)
+	assert.Len(manifest.Cycles, 2)
+
+	read := make([]*store.StoredDelegationState, 0)
+	readManifest, err := Read(bytes.NewReader(data), func(manifest *Manifest) error {
+		return manifest.Validate("NetXtest")
+	}, func(cycle int64, cycleStates []*store.StoredDelegationState) error {
+		assert.Len(cycleStates, 1)
+		read = append(read, cycleStates...)
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(manifest.Cycles, readManifest.Cycles)
+	assert.Equal(states, read)
+}
+
+func TestSnapshotValidation(t *testing.T) {
+	assert := assert.New(t)
+
+	_, data := writeTestSnapshot(t, testStates())
+	applied := false
+	_, err := Read(bytes.NewReader(data), func(manifest *Manifest) error {
+		return manifest.Validate("NetXother")
+	}, func(cycle int64, states []*store.StoredDelegationState) error {
+		applied = true
+		return nil
+	})
+	assert.ErrorIs(err, constants.ErrSnapshotChainMismatch)
+	assert.False(applied)
+
+	_, err = decodeCycle([]byte("{}\n"), &CycleEntry{Cycle: 745, States: 1, Sha256: "00"})
+	assert.ErrorIs(err, constants.ErrSnapshotChecksumMismatch)
+}
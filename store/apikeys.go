@@ -0,0 +1,149 @@
+package store
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"gorm.io/gorm"
+)
+
+const apiKeyPrefix = "pr_"
+
+// ApiKey grants its own rate limit and daily quota on the public api. Only the hash of the key is stored.
+type ApiKey struct {
+	Id                  int64  `json:"id" gorm:"primaryKey"`
+	Name                string `json:"name"`
+	Prefix              string `json:"prefix"`
+	KeyHash             string `json:"-"`
+	RateLimitMax        int    `json:"rate_limit_max"`
+	RateLimitExpiration int    `json:"rate_limit_expiration"`
+	// requests per UTC day, 0 is unlimited
+	DailyQuota int64      `json:"daily_quota"`
+	Requests   int64      `json:"requests"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+type ApiKeyWithUsage struct {
+	ApiKey
+	RequestsToday int64 `json:"requests_today"`
+}
+
+func hashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateApiKey stores a new key and returns it together with the secret which is not retrievable later
+func (s *Store) CreateApiKey(name string, rateLimitMax, rateLimitExpiration int, dailyQuota int64) (*ApiKey, string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+
+	apiKey := &ApiKey{
+		Name:                name,
+		Prefix:              key[:len(apiKeyPrefix)+8],
+		KeyHash:             hashApiKey(key),
+		RateLimitMax:        rateLimitMax,
+		RateLimitExpiration: rateLimitExpiration,
+		DailyQuota:          dailyQuota,
+		CreatedAt:           time.Now().UTC(),
+	}
+	if err := s.db.Create(apiKey).Error; err != nil {
+		return nil, "", err
+	}
+	return apiKey, key, nil
+}
+
+// GetApiKey looks up an active key by its secret
+func (s *Store) GetApiKey(key string) (*ApiKey, error) {
+	var apiKey ApiKey
+	if err := s.db.Where("key_hash = ? AND revoked_at IS NULL", hashApiKey(key)).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Join(constants.ErrNotFound, err)
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+func (s *Store) ListApiKeys() ([]ApiKeyWithUsage, error) {
+	var result []ApiKeyWithUsage
+	err := s.db.Table("api_keys AS k").
+		Select("k.*, COALESCE(u.requests, 0) AS requests_today").
+		Joins("LEFT JOIN api_key_usage AS u ON u.key_id = k.id AND u.day = ?", time.Now().UTC().Format(time.DateOnly)).
+		Order("k.id").
+		Scan(&result).Error
+	return result, err
+}
+
+func (s *Store) RevokeApiKey(id int64) error {
+	result := s.db.Model(&ApiKey{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now().UTC())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return constants.ErrNotFound
+	}
+	return nil
+}
+
+// GetApiKeyUsage returns the number of requests of the key in the UTC day
+func (s *Store) GetApiKeyUsage(id int64, day string) (int64, error) {
+	var requests int64
+	err := s.db.Raw("SELECT COALESCE(SUM(requests), 0) FROM api_key_usage WHERE key_id = ? AND day = ?", id, day).Scan(&requests).Error
+	return requests, err
+}
+
+// AddApiKeyUsage adds the requests counted by key id to the UTC day in a single transaction and returns
+// the stored requests of the day by key id, including requests counted by other replicas
+func (s *Store) AddApiKeyUsage(day string, usage map[int64]int64, lastUsedAt time.Time) (map[int64]int64, error) {
+	result := make(map[int64]int64, len(usage))
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for id, requests := range usage {
+			if err := tx.Model(&ApiKey{}).Where("id = ?", id).Updates(map[string]any{
+				"requests":     gorm.Expr("requests + ?", requests),
+				"last_used_at": lastUsedAt,
+			}).Error; err != nil {
+				return err
+			}
+			var today int64
+			if err := tx.Raw(`INSERT INTO api_key_usage (key_id, day, requests) VALUES (?, ?, ?)
+				ON CONFLICT (key_id, day) DO UPDATE SET requests = api_key_usage.requests + EXCLUDED.requests
+				RETURNING requests`, id, day, requests).Scan(&today).Error; err != nil {
+				return err
+			}
+			result[id] = today
+		}
+		return nil
+	})
+	return result, err
+}
+
+// HitRateLimit counts a hit of the key in a fixed window of the expiration and returns the hits in the window
+// and when it resets. The upsert is atomic so replicas sharing the database enforce a common limit.
+func (s *Store) HitRateLimit(key string, expiration time.Duration) (int, time.Time, error) {
+	var result struct {
+		Hits      int
+		ExpiresAt time.Time
+	}
+	now := time.Now().UTC()
+	err := s.db.Raw(`INSERT INTO rate_limits (key, hits, expires_at) VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			hits = CASE WHEN rate_limits.expires_at <= ? THEN 1 ELSE rate_limits.hits + 1 END,
+			expires_at = CASE WHEN rate_limits.expires_at <= ? THEN EXCLUDED.expires_at ELSE rate_limits.expires_at END
+		RETURNING hits, expires_at`, key, now.Add(expiration), now, now).Scan(&result).Error
+	return result.Hits, result.ExpiresAt, err
+}
+
+// DeleteExpiredRateLimits removes windows which are over
+func (s *Store) DeleteExpiredRateLimits() error {
+	return s.db.Exec("DELETE FROM rate_limits WHERE expires_at <= ?", time.Now().UTC()).Error
+}
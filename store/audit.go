@@ -0,0 +1,36 @@
+package store
+
+import "time"
+
+// AuditEntry records an action triggered through the private api
+type AuditEntry struct {
+	Id    int64     `json:"id" gorm:"primaryKey"`
+	Time  time.Time `json:"time"`
+	Actor string    `json:"actor"`
+	Role  string    `json:"role"`
+	// e.g. fetch_cycle, prune or export
+	Action string `json:"action"`
+	// method and url including the query
+	Request       string `json:"request"`
+	RemoteAddress string `json:"remote_address"`
+	Status        int    `json:"status"`
+}
+
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
+
+func (s *Store) AppendAuditEntry(entry *AuditEntry) error {
+	return s.db.Create(entry).Error
+}
+
+// ListAuditEntries returns the latest entries, only entries older than before if it is set
+func (s *Store) ListAuditEntries(limit int, before int64) ([]AuditEntry, error) {
+	db := s.db.Order("id DESC").Limit(limit)
+	if before > 0 {
+		db = db.Where("id < ?", before)
+	}
+	var result []AuditEntry
+	err := db.Find(&result).Error
+	return result, err
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+type BackfillCheckpointStatus string
+
+const (
+	BackfillCheckpointStatusCompleted BackfillCheckpointStatus = "completed"
+	BackfillCheckpointStatusFailed    BackfillCheckpointStatus = "failed"
+)
+
+// BackfillCheckpoint records progress of a backfill job so it can be resumed
+type BackfillCheckpoint struct {
+	Job       string                   `json:"job" gorm:"primaryKey"`
+	Cycle     int64                    `json:"cycle" gorm:"primaryKey"`
+	Status    BackfillCheckpointStatus `json:"status"`
+	Failures  int                      `json:"failures"`
+	Error     string                   `json:"error"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+func (s *Store) GetCompletedBackfillCycles(job string) ([]int64, error) {
+	var cycles []int64
+	err := s.db.Model(&BackfillCheckpoint{}).Where("job = ? AND status = ?", job, BackfillCheckpointStatusCompleted).Pluck("cycle", &cycles).Error
+	return cycles, err
+}
+
+func (s *Store) StoreBackfillCheckpoint(checkpoint *BackfillCheckpoint) error {
+	return s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(checkpoint).Error
+}
+
+func (s *Store) DeleteBackfillCheckpoints(job string) error {
+	return s.db.Where("job = ?", job).Delete(&BackfillCheckpoint{}).Error
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/common"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// DelegationStateBalance is the normalized form of a single entry of StoredDelegationState.Balances
+type DelegationStateBalance struct {
+	Cycle             int64   `json:"cycle" gorm:"primaryKey"`
+	Baker             Address `json:"baker" gorm:"primaryKey"`
+	Delegator         Address `json:"delegator" gorm:"primaryKey"`
+	DelegatedBalance  int64   `json:"delegated_balance"`
+	StakedBalance     int64   `json:"staked_balance"`
+	OverstakedBalance int64   `json:"overstaked_balance"`
+}
+
+type DelegationStateBalanceWithStatus struct {
+	DelegationStateBalance
+	Status DelegationStateStatus `json:"status"`
+}
+
+func balancesFromState(state *StoredDelegationState) []DelegationStateBalance {
+	result := make([]DelegationStateBalance, 0, len(state.Balances))
+	for addr, balances := range state.Balances {
+		result = append(result, DelegationStateBalance{
+			Cycle:             state.Cycle,
+			Baker:             state.Delegate,
+			Delegator:         Address{addr},
+			DelegatedBalance:  balances.DelegatedBalance,
+			StakedBalance:     balances.StakedBalance,
+			OverstakedBalance: balances.OverstakedBalance,
+		})
+	}
+	return result
+}
+
+// replaceBalances keeps the normalized balances in sync with the jsonb column, has to run in the transaction writing the state
+func replaceBalances(tx *gorm.DB, state *StoredDelegationState) error {
+	if err := tx.Where("cycle = ? AND baker = ?", state.Cycle, state.Delegate).Delete(&DelegationStateBalance{}).Error; err != nil {
+		return err
+	}
+	balances := balancesFromState(state)
+	if len(balances) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(balances, constants.STORE_BATCH_SIZE).Error
+}
+
+// GetDelegatorBalances returns balances of the delegator with every baker it was delegating to in the cycle
+func (s *Store) GetDelegatorBalances(delegator mavryk.Address, cycle int64) ([]DelegationStateBalance, error) {
+	var result []DelegationStateBalance
+	err := s.db.Where("delegator = ? AND cycle = ?", delegator.String(), cycle).Order("baker").Find(&result).Error
+	return result, err
+}
+
+// GetTopDelegators returns up to limit delegators of the baker with the highest delegated and staked balance, the baker itself excluded
+func (s *Store) GetTopDelegators(baker mavryk.Address, cycle int64, limit int) ([]DelegationStateBalance, error) {
+	var result []DelegationStateBalance
+	err := s.db.Where("baker = ? AND cycle = ? AND delegator <> baker", baker.String(), cycle).
+		Order("delegated_balance + staked_balance DESC, delegator").
+		Limit(limit).
+		Find(&result).Error
+	return result, err
+}
+
+// ForEachDelegationStateBalance iterates over normalized balances of cycles in range [fromCycle, toCycle]
+// ordered by cycle, baker and delegator. Optionally limited to the bakers passed.
+func (s *Store) ForEachDelegationStateBalance(fromCycle, toCycle int64, bakers []mavryk.Address, f func(balance *DelegationStateBalanceWithStatus) error) error {
+	query := s.db.Table("delegation_state_balances AS b").
+		Select("b.*, s.status").
+		Joins("JOIN stored_delegation_states AS s ON s.delegate = b.baker AND s.cycle = b.cycle").
+		Where("b.cycle >= ? AND b.cycle <= ?", fromCycle, toCycle)
+	if len(bakers) > 0 {
+		query = query.Where("b.baker IN ?", lo.Map(bakers, func(addr mavryk.Address, _ int) string { return addr.String() }))
+	}
+
+	rows, err := query.Order("b.cycle, b.baker, b.delegator").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var balance DelegationStateBalanceWithStatus
+		if err := s.db.ScanRows(rows, &balance); err != nil {
+			return err
+		}
+		if err := f(&balance); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// bakerBakingPower is the baking power of the baker balances, overstaked balance counts as delegated
+func bakerBakingPower(cycle, ownStaked, ownDelegated, externalStaked, externalDelegated, externalOverstaked int64) int64 {
+	return common.BakingPower(cycle, ownStaked+externalStaked-externalOverstaked, ownDelegated+externalDelegated+externalOverstaked)
+}
+
+// bakerAggregates selects per baker aggregates of the cycle from the normalized balances
+func bakerAggregates(db *gorm.DB, cycle int64) *gorm.DB {
+	return db.Table("stored_delegation_states AS s").
+		Select(`s.cycle, s.delegate AS baker,
+			COALESCE(SUM(CASE WHEN b.delegator = b.baker THEN b.staked_balance END), 0) AS own_staked,
+			COALESCE(SUM(CASE WHEN b.delegator = b.baker THEN b.delegated_balance END), 0) AS own_delegated,
+			COALESCE(SUM(CASE WHEN b.delegator <> b.baker THEN b.staked_balance END), 0) AS external_staked,
+			COALESCE(SUM(CASE WHEN b.delegator <> b.baker THEN b.delegated_balance END), 0) AS external_delegated,
+			COALESCE(SUM(CASE WHEN b.delegator <> b.baker THEN b.overstaked_balance END), 0) AS external_overstaked,
+			COUNT(b.delegator) FILTER (WHERE b.delegator <> b.baker AND b.delegator <> ?) AS delegators`, mavryk.BurnAddress.String()).
+		Joins("LEFT JOIN delegation_state_balances AS b ON b.baker = s.delegate AND b.cycle = s.cycle").
+		Where("s.cycle = ?", cycle).
+		Group("s.cycle, s.delegate")
+}
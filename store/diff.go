@@ -0,0 +1,74 @@
+package store
+
+import (
+	"github.com/mavryk-network/mvgo/mavryk"
+)
+
+type DelegationStateCycleDiffTotals struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+	// balances of delegators which joined or left between the cycles
+	JoinedDelegatedBalance int64 `json:"joined_delegated_balance"`
+	JoinedStakedBalance    int64 `json:"joined_staked_balance"`
+	LeftDelegatedBalance   int64 `json:"left_delegated_balance"`
+	LeftStakedBalance      int64 `json:"left_staked_balance"`
+	// net change over all delegators
+	DelegatedBalanceDiff  int64 `json:"delegated_balance_diff"`
+	StakedBalanceDiff     int64 `json:"staked_balance_diff"`
+	OverstakedBalanceDiff int64 `json:"overstaked_balance_diff"`
+}
+
+// DelegationStateCycleDiff compares the delegators of a delegate in two cycles
+type DelegationStateCycleDiff struct {
+	Delegate  mavryk.Address   `json:"delegate"`
+	FromCycle int64            `json:"from_cycle"`
+	ToCycle   int64            `json:"to_cycle"`
+	Added     []mavryk.Address `json:"added"`
+	Removed   []mavryk.Address `json:"removed"`
+	// every delegator whose balances differ including added and removed ones
+	Changes []DelegatorBalanceChange       `json:"changes"`
+	Totals  DelegationStateCycleDiffTotals `json:"totals"`
+}
+
+// DiffDelegationStateCycles compares the stored states of the delegate in two cycles
+func (s *Store) DiffDelegationStateCycles(delegate mavryk.Address, fromCycle, toCycle int64) (*DelegationStateCycleDiff, error) {
+	from, err := s.GetDelegationState(delegate, fromCycle)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.GetDelegationState(delegate, toCycle)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DelegationStateCycleDiff{
+		Delegate:  delegate,
+		FromCycle: fromCycle,
+		ToCycle:   toCycle,
+		Added:     make([]mavryk.Address, 0),
+		Removed:   make([]mavryk.Address, 0),
+		Changes:   DiffDelegationStateBalances(from.Balances, to.Balances),
+	}
+	totals := &result.Totals
+	for _, change := range result.Changes {
+		switch {
+		case change.From == nil:
+			result.Added = append(result.Added, change.Delegator)
+			totals.JoinedDelegatedBalance += change.To.DelegatedBalance
+			totals.JoinedStakedBalance += change.To.StakedBalance
+		case change.To == nil:
+			result.Removed = append(result.Removed, change.Delegator)
+			totals.LeftDelegatedBalance += change.From.DelegatedBalance
+			totals.LeftStakedBalance += change.From.StakedBalance
+		default:
+			totals.Changed++
+		}
+		totals.DelegatedBalanceDiff += change.DelegatedBalanceDiff
+		totals.StakedBalanceDiff += change.StakedBalanceDiff
+		totals.OverstakedBalanceDiff += change.OverstakedBalanceDiff
+	}
+	totals.Added = len(result.Added)
+	totals.Removed = len(result.Removed)
+	return result, nil
+}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/common"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/samber/lo"
+
+	"gorm.io/gorm"
+)
+
+// DelegationStateHistory is a revision of a delegation state replaced by a later write
+type DelegationStateHistory struct {
+	Delegate   Address                 `json:"delegate" gorm:"primaryKey"`
+	Cycle      int64                   `json:"cycle" gorm:"primaryKey"`
+	Revision   int64                   `json:"revision" gorm:"primaryKey"`
+	Status     DelegationStateStatus   `json:"status"`
+	Balances   DelegationStateBalances `json:"balances" gorm:"type:jsonb;default:'{}'"`
+	Reason     RevisionReason          `json:"reason"`
+	StoredAt   time.Time               `json:"stored_at"`
+	ReplacedAt time.Time               `json:"replaced_at"`
+
+	StakingParameters *StakingParameters `json:"staking_parameters,omitempty" gorm:"type:jsonb"`
+}
+
+func (DelegationStateHistory) TableName() string {
+	return "delegation_state_history"
+}
+
+// lockDelegationState serializes writers of the delegate and cycle until the transaction ends,
+// it holds even if the state does not exist yet
+func lockDelegationState(tx *gorm.DB, state *StoredDelegationState) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?), ?)", state.Delegate.String(), state.Cycle).Error
+}
+
+// upsertDelegationState writes the state and its normalized balances. If the state exists
+// it is moved to the history and the revision is bumped. Has to run in a transaction.
+func upsertDelegationState(tx *gorm.DB, state *StoredDelegationState) error {
+	if err := lockDelegationState(tx, state); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	state.StoredAt = now
+	// copies nothing if the state does not exist yet
+	err := tx.Exec(`INSERT INTO delegation_state_history (delegate, cycle, revision, status, balances, reason, stored_at, replaced_at, staking_parameters)
+		SELECT delegate, cycle, revision, status, balances, reason, stored_at, ?, staking_parameters
+		FROM stored_delegation_states WHERE delegate = ? AND cycle = ?`, now, state.Delegate, state.Cycle).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Raw(`INSERT INTO stored_delegation_states (delegate, cycle, status, balances, staking_parameters, revision, reason, stored_at)
+		VALUES (?, ?, ?, ?, ?, 1, ?, ?)
+		ON CONFLICT (delegate, cycle) DO UPDATE SET
+			status = EXCLUDED.status,
+			balances = EXCLUDED.balances,
+			staking_parameters = EXCLUDED.staking_parameters,
+			revision = stored_delegation_states.revision + 1,
+			reason = EXCLUDED.reason,
+			stored_at = EXCLUDED.stored_at
+		RETURNING revision`,
+		state.Delegate, state.Cycle, int(state.Status), state.Balances, state.StakingParameters, string(state.Reason), state.StoredAt).
+		Scan(&state.Revision).Error
+	if err != nil {
+		return err
+	}
+	return replaceBalances(tx, state)
+}
+
+// DelegationStateRevision is a stored or replaced revision of a delegation state
+type DelegationStateRevision struct {
+	Revision   int64                   `json:"revision"`
+	Status     DelegationStateStatus   `json:"status"`
+	Reason     RevisionReason          `json:"reason"`
+	StoredAt   time.Time               `json:"stored_at"`
+	ReplacedAt *time.Time              `json:"replaced_at"`
+	Current    bool                    `json:"current"`
+	Delegators int                     `json:"delegators"`
+	Balances   DelegationStateBalances `json:"-"`
+}
+
+// GetDelegationStateRevisions lists all revisions ordered from the oldest, balances are omitted
+func (s *Store) GetDelegationStateRevisions(delegate mavryk.Address, cycle int64) ([]DelegationStateRevision, error) {
+	var history []DelegationStateHistory
+	if err := s.db.Where("delegate = ? AND cycle = ?", delegate.String(), cycle).Order("revision").Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]DelegationStateRevision, 0, len(history)+1)
+	for _, item := range history {
+		replacedAt := item.ReplacedAt
+		result = append(result, DelegationStateRevision{
+			Revision:   item.Revision,
+			Status:     item.Status,
+			Reason:     item.Reason,
+			StoredAt:   item.StoredAt,
+			ReplacedAt: &replacedAt,
+			Delegators: len(item.Balances),
+		})
+	}
+
+	current, err := s.GetDelegationState(delegate, cycle)
+	switch {
+	case err == nil:
+		result = append(result, DelegationStateRevision{
+			Revision:   current.Revision,
+			Status:     current.Status,
+			Reason:     current.Reason,
+			StoredAt:   current.StoredAt,
+			Current:    true,
+			Delegators: len(current.Balances),
+		})
+	case !errors.Is(err, constants.ErrNotFound):
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, constants.ErrNotFound
+	}
+	return result, nil
+}
+
+// GetDelegationStateRevision returns the revision including balances, 0 means the current revision
+func (s *Store) GetDelegationStateRevision(delegate mavryk.Address, cycle, revision int64) (*DelegationStateRevision, error) {
+	current, err := s.GetDelegationState(delegate, cycle)
+	if err != nil && !errors.Is(err, constants.ErrNotFound) {
+		return nil, err
+	}
+	if current != nil && (revision == 0 || revision == current.Revision) {
+		return &DelegationStateRevision{
+			Revision:   current.Revision,
+			Status:     current.Status,
+			Reason:     current.Reason,
+			StoredAt:   current.StoredAt,
+			Current:    true,
+			Delegators: len(current.Balances),
+			Balances:   current.Balances,
+		}, nil
+	}
+
+	var item DelegationStateHistory
+	if err := s.db.Where("delegate = ? AND cycle = ? AND revision = ?", delegate.String(), cycle, revision).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Join(constants.ErrNotFound, fmt.Errorf("revision %d", revision))
+		}
+		return nil, err
+	}
+	return &DelegationStateRevision{
+		Revision:   item.Revision,
+		Status:     item.Status,
+		Reason:     item.Reason,
+		StoredAt:   item.StoredAt,
+		ReplacedAt: &item.ReplacedAt,
+		Delegators: len(item.Balances),
+		Balances:   item.Balances,
+	}, nil
+}
+
+type DelegatorBalanceChange struct {
+	Delegator             mavryk.Address            `json:"delegator"`
+	From                  *common.DelegatorBalances `json:"from"`
+	To                    *common.DelegatorBalances `json:"to"`
+	DelegatedBalanceDiff  int64                     `json:"delegated_balance_diff"`
+	StakedBalanceDiff     int64                     `json:"staked_balance_diff"`
+	OverstakedBalanceDiff int64                     `json:"overstaked_balance_diff"`
+}
+
+// DiffDelegationStateBalances lists delegators whose balances differ, ordered by address.
+// From or To is nil if the delegator is missing in that revision.
+func DiffDelegationStateBalances(from, to DelegationStateBalances) []DelegatorBalanceChange {
+	result := make([]DelegatorBalanceChange, 0)
+	for _, addr := range lo.Union(lo.Keys(from), lo.Keys(to)) {
+		change := DelegatorBalanceChange{Delegator: addr}
+		fromBalances, inFrom := from[addr]
+		toBalances, inTo := to[addr]
+		if inFrom && inTo && fromBalances == toBalances {
+			continue
+		}
+		if inFrom {
+			change.From = &fromBalances
+		}
+		if inTo {
+			change.To = &toBalances
+		}
+		change.DelegatedBalanceDiff = toBalances.DelegatedBalance - fromBalances.DelegatedBalance
+		change.StakedBalanceDiff = toBalances.StakedBalance - fromBalances.StakedBalance
+		change.OverstakedBalanceDiff = toBalances.OverstakedBalance - fromBalances.OverstakedBalance
+		result = append(result, change)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Delegator.String() < result[j].Delegator.String() })
+	return result
+}
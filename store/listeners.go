@@ -0,0 +1,30 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+)
+
+// ChangeListener is called after stored delegation states changed, delegate is nil if the whole cycle changed
+type ChangeListener func(cycle int64, delegate *mavryk.Address)
+
+type changeListeners struct {
+	mtx       sync.RWMutex
+	listeners []ChangeListener
+}
+
+// OnDelegationStatesChanged registers the listener, it is called synchronously after the change is committed
+func (s *Store) OnDelegationStatesChanged(listener ChangeListener) {
+	s.changes.mtx.Lock()
+	defer s.changes.mtx.Unlock()
+	s.changes.listeners = append(s.changes.listeners, listener)
+}
+
+func (s *Store) notifyChanged(cycle int64, delegate *mavryk.Address) {
+	s.changes.mtx.RLock()
+	defer s.changes.mtx.RUnlock()
+	for _, listener := range s.changes.listeners {
+		listener(cycle, delegate)
+	}
+}
@@ -0,0 +1,392 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"gorm.io/gorm"
+)
+
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// migrations are applied in order and never edited once released, changes go into a new migration.
+// The first migrations use IF NOT EXISTS so databases created by AutoMigrate are adopted as they are.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create stored_delegation_states",
+		up: `CREATE TABLE IF NOT EXISTS stored_delegation_states (
+			delegate text NOT NULL,
+			cycle bigint NOT NULL,
+			status bigint,
+			balances jsonb DEFAULT '{}',
+			PRIMARY KEY (delegate, cycle)
+		)`,
+		down: `DROP TABLE IF EXISTS stored_delegation_states`,
+	},
+	{
+		version: 2,
+		name:    "create backfill_checkpoints",
+		up: `CREATE TABLE IF NOT EXISTS backfill_checkpoints (
+			job text NOT NULL,
+			cycle bigint NOT NULL,
+			status text,
+			failures bigint,
+			error text,
+			updated_at timestamptz,
+			PRIMARY KEY (job, cycle)
+		)`,
+		down: `DROP TABLE IF EXISTS backfill_checkpoints`,
+	},
+	{
+		version: 3,
+		name:    "create delegation_state_balances",
+		up: `CREATE TABLE delegation_state_balances (
+			cycle bigint NOT NULL,
+			baker text NOT NULL,
+			delegator text NOT NULL,
+			delegated_balance bigint NOT NULL DEFAULT 0,
+			staked_balance bigint NOT NULL DEFAULT 0,
+			overstaked_balance bigint NOT NULL DEFAULT 0,
+			PRIMARY KEY (cycle, baker, delegator),
+			FOREIGN KEY (baker, cycle) REFERENCES stored_delegation_states (delegate, cycle) ON DELETE CASCADE
+		);
+		CREATE INDEX delegation_state_balances_delegator_idx ON delegation_state_balances (delegator, cycle);
+		INSERT INTO delegation_state_balances (cycle, baker, delegator, delegated_balance, staked_balance, overstaked_balance)
+		SELECT s.cycle, s.delegate, b.key,
+			COALESCE((b.value->>'delegated_balance')::bigint, 0),
+			COALESCE((b.value->>'staked_balance')::bigint, 0),
+			COALESCE((b.value->>'overstaked_balance')::bigint, 0)
+		FROM stored_delegation_states AS s, jsonb_each(s.balances) AS b`,
+		down: `DROP TABLE IF EXISTS delegation_state_balances`,
+	},
+	{
+		version: 4,
+		name:    "add revision and delegation_state_history",
+		up: `ALTER TABLE stored_delegation_states ADD COLUMN revision bigint NOT NULL DEFAULT 1;
+		CREATE TABLE delegation_state_history (
+			delegate text NOT NULL,
+			cycle bigint NOT NULL,
+			revision bigint NOT NULL,
+			status bigint,
+			balances jsonb DEFAULT '{}',
+			replaced_at timestamptz NOT NULL,
+			PRIMARY KEY (delegate, cycle, revision)
+		)`,
+		down: `DROP TABLE IF EXISTS delegation_state_history;
+		ALTER TABLE stored_delegation_states DROP COLUMN IF EXISTS revision`,
+	},
+	{
+		version: 5,
+		name:    "add revision reason and timestamp",
+		up: `ALTER TABLE stored_delegation_states ADD COLUMN reason text NOT NULL DEFAULT 'unknown';
+		ALTER TABLE stored_delegation_states ADD COLUMN stored_at timestamptz NOT NULL DEFAULT now();
+		ALTER TABLE delegation_state_history ADD COLUMN reason text NOT NULL DEFAULT 'unknown';
+		ALTER TABLE delegation_state_history ADD COLUMN stored_at timestamptz NOT NULL DEFAULT now()`,
+		down: `ALTER TABLE delegation_state_history DROP COLUMN IF EXISTS stored_at;
+		ALTER TABLE delegation_state_history DROP COLUMN IF EXISTS reason;
+		ALTER TABLE stored_delegation_states DROP COLUMN IF EXISTS stored_at;
+		ALTER TABLE stored_delegation_states DROP COLUMN IF EXISTS reason`,
+	},
+	{
+		version: 6,
+		name:    "create cycle_baker_aggregates",
+		up: `CREATE TABLE cycle_baker_aggregates (
+			cycle bigint NOT NULL,
+			baker text NOT NULL,
+			own_staked bigint NOT NULL DEFAULT 0,
+			own_delegated bigint NOT NULL DEFAULT 0,
+			external_staked bigint NOT NULL DEFAULT 0,
+			external_delegated bigint NOT NULL DEFAULT 0,
+			delegators bigint NOT NULL DEFAULT 0,
+			PRIMARY KEY (cycle, baker)
+		)`,
+		down: `DROP TABLE IF EXISTS cycle_baker_aggregates`,
+	},
+	{
+		version: 7,
+		name:    "create cycle_summaries",
+		up: `CREATE TABLE cycle_summaries (
+			cycle bigint PRIMARY KEY,
+			merkle_root text NOT NULL,
+			leaves bigint NOT NULL DEFAULT 0,
+			bakers bigint NOT NULL DEFAULT 0,
+			computed_at timestamptz NOT NULL
+		)`,
+		down: `DROP TABLE IF EXISTS cycle_summaries`,
+	},
+	{
+		version: 8,
+		name:    "create api_keys and rate_limits",
+		up: `CREATE TABLE api_keys (
+			id bigserial PRIMARY KEY,
+			name text NOT NULL,
+			prefix text NOT NULL,
+			key_hash text NOT NULL UNIQUE,
+			rate_limit_max bigint NOT NULL,
+			rate_limit_expiration bigint NOT NULL,
+			daily_quota bigint NOT NULL DEFAULT 0,
+			requests bigint NOT NULL DEFAULT 0,
+			last_used_at timestamptz,
+			created_at timestamptz NOT NULL,
+			revoked_at timestamptz
+		);
+		CREATE TABLE api_key_usage (
+			key_id bigint NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE,
+			day date NOT NULL,
+			requests bigint NOT NULL DEFAULT 0,
+			PRIMARY KEY (key_id, day)
+		);
+		CREATE TABLE rate_limits (
+			key text PRIMARY KEY,
+			hits bigint NOT NULL,
+			expires_at timestamptz NOT NULL
+		)`,
+		down: `DROP TABLE IF EXISTS rate_limits;
+		DROP TABLE IF EXISTS api_key_usage;
+		DROP TABLE IF EXISTS api_keys`,
+	},
+	{
+		version: 9,
+		name:    "create audit_log",
+		up: `CREATE TABLE audit_log (
+			id bigserial PRIMARY KEY,
+			time timestamptz NOT NULL,
+			actor text NOT NULL,
+			role text NOT NULL,
+			action text NOT NULL,
+			request text NOT NULL,
+			remote_address text NOT NULL,
+			status integer NOT NULL
+		);
+		CREATE INDEX audit_log_time_idx ON audit_log (time)`,
+		down: `DROP TABLE IF EXISTS audit_log`,
+	},
+	{
+		version: 10,
+		name:    "create cycle_statistics",
+		up: `CREATE TABLE cycle_statistics (
+			cycle bigint PRIMARY KEY,
+			statistics jsonb NOT NULL,
+			computed_at timestamptz NOT NULL
+		)`,
+		down: `DROP TABLE IF EXISTS cycle_statistics`,
+	},
+	{
+		version: 11,
+		name:    "index balances and aggregates by baker",
+		up: `CREATE INDEX delegation_state_balances_baker_idx ON delegation_state_balances (baker, cycle);
+		CREATE INDEX cycle_baker_aggregates_baker_idx ON cycle_baker_aggregates (baker, cycle)`,
+		down: `DROP INDEX IF EXISTS delegation_state_balances_baker_idx;
+		DROP INDEX IF EXISTS cycle_baker_aggregates_baker_idx`,
+	},
+	{
+		version: 12,
+		name:    "add staking_parameters",
+		up: `ALTER TABLE stored_delegation_states ADD COLUMN staking_parameters jsonb;
+		ALTER TABLE delegation_state_history ADD COLUMN staking_parameters jsonb`,
+		down: `ALTER TABLE delegation_state_history DROP COLUMN IF EXISTS staking_parameters;
+		ALTER TABLE stored_delegation_states DROP COLUMN IF EXISTS staking_parameters`,
+	},
+	{
+		version: 13,
+		name:    "version cycle_summaries",
+		up: `ALTER TABLE cycle_summaries ADD COLUMN version bigint NOT NULL DEFAULT 1;
+		ALTER TABLE cycle_summaries DROP CONSTRAINT cycle_summaries_pkey;
+		ALTER TABLE cycle_summaries ADD PRIMARY KEY (cycle, version)`,
+		down: `DELETE FROM cycle_summaries AS s WHERE version < (SELECT MAX(version) FROM cycle_summaries WHERE cycle = s.cycle);
+		ALTER TABLE cycle_summaries DROP CONSTRAINT cycle_summaries_pkey;
+		ALTER TABLE cycle_summaries ADD PRIMARY KEY (cycle);
+		ALTER TABLE cycle_summaries DROP COLUMN version`,
+	},
+	{
+		version: 14,
+		// aggregates stored before keep zero, their balances are gone
+		name: "add external_overstaked to cycle_baker_aggregates",
+		up:   `ALTER TABLE cycle_baker_aggregates ADD COLUMN external_overstaked bigint NOT NULL DEFAULT 0`,
+		down: `ALTER TABLE cycle_baker_aggregates DROP COLUMN IF EXISTS external_overstaked`,
+	},
+}
+
+type SchemaVersion struct {
+	Version   int       `gorm:"primaryKey"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaVersion) TableName() string {
+	return "schema_version"
+}
+
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+// LatestSchemaVersion is the schema version the binary was built for
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+func (s *Store) ensureSchemaVersionTable() error {
+	return s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`).Error
+}
+
+// SchemaVersion returns the version of the last applied migration, 0 for an empty database
+func (s *Store) SchemaVersion() (int, error) {
+	if err := s.ensureSchemaVersionTable(); err != nil {
+		return 0, err
+	}
+	var version int
+	err := s.db.Model(&SchemaVersion{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+func (s *Store) MigrationStatus() ([]MigrationStatus, error) {
+	if err := s.ensureSchemaVersionTable(); err != nil {
+		return nil, err
+	}
+	var applied []SchemaVersion
+	if err := s.db.Order("version").Find(&applied).Error; err != nil {
+		return nil, err
+	}
+	appliedAt := make(map[int]time.Time, len(applied))
+	for _, version := range applied {
+		appliedAt[version.Version] = version.AppliedAt
+	}
+
+	result := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.version, Name: m.name}
+		if at, ok := appliedAt[m.version]; ok {
+			status.AppliedAt = &at
+			delete(appliedAt, m.version)
+		}
+		result = append(result, status)
+	}
+	// versions applied by a newer binary
+	for version, at := range appliedAt {
+		at := at
+		result = append(result, MigrationStatus{Version: version, Name: "unknown", AppliedAt: &at})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+func (s *Store) checkSchemaVersion() (int, error) {
+	version, err := s.SchemaVersion()
+	if err != nil {
+		return 0, err
+	}
+	if version > LatestSchemaVersion() {
+		return version, errors.Join(constants.ErrDatabaseSchemaTooNew, fmt.Errorf("database is on version %d, binary supports up to %d", version, LatestSchemaVersion()))
+	}
+	return version, nil
+}
+
+// withMigrationLock runs f while holding a session advisory lock, replicas starting at the same time
+// migrate one after another and see the versions applied by the others
+func (s *Store) withMigrationLock(f func() error) error {
+	return s.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(hashtext('schema_version'))").Error; err != nil {
+			return err
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(hashtext('schema_version'))").Error; err != nil {
+				slog.Warn("failed to release the migration lock", "error", err.Error())
+			}
+		}()
+		return f()
+	})
+}
+
+// MigrateUp applies pending migrations up to and including target, 0 means the latest version
+func (s *Store) MigrateUp(target int) (applied []int, err error) {
+	if target == 0 {
+		target = LatestSchemaVersion()
+	}
+	err = s.withMigrationLock(func() error {
+		applied, err = s.migrateUp(target)
+		return err
+	})
+	return applied, err
+}
+
+func (s *Store) migrateUp(target int) ([]int, error) {
+	current, err := s.checkSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]int, 0)
+	for _, m := range migrations {
+		if m.version <= current || m.version > target {
+			continue
+		}
+		slog.Info("applying migration", "version", m.version, "name", m.name)
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&SchemaVersion{Version: m.version, Name: m.name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return applied, errors.Join(constants.ErrMigrationFailed, fmt.Errorf("version %d (%s)", m.version, m.name), err)
+		}
+		applied = append(applied, m.version)
+	}
+	return applied, nil
+}
+
+// MigrateDown reverts applied migrations newer than target
+func (s *Store) MigrateDown(target int) (reverted []int, err error) {
+	if target < 0 {
+		return nil, fmt.Errorf("invalid target version %d", target)
+	}
+	err = s.withMigrationLock(func() error {
+		reverted, err = s.migrateDown(target)
+		return err
+	})
+	return reverted, err
+}
+
+func (s *Store) migrateDown(target int) ([]int, error) {
+	current, err := s.checkSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	reverted := make([]int, 0)
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.version > current || m.version <= target {
+			continue
+		}
+		slog.Info("reverting migration", "version", m.version, "name", m.name)
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaVersion{}, m.version).Error
+		})
+		if err != nil {
+			return reverted, errors.Join(constants.ErrMigrationFailed, fmt.Errorf("version %d (%s)", m.version, m.name), err)
+		}
+		reverted = append(reverted, m.version)
+	}
+	return reverted, nil
+}
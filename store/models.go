@@ -4,6 +4,8 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"sort"
+	"time"
 
 	"github.com/mavryk-network/mvgo/mavryk"
 	"github.com/mavryk-network/protocol-rewards/common"
@@ -16,6 +18,27 @@ const (
 	DelegationStateStatusMinimumNotAvailable                       // 1
 )
 
+func (s DelegationStateStatus) String() string {
+	switch s {
+	case DelegationStateStatusOk:
+		return "ok"
+	case DelegationStateStatusMinimumNotAvailable:
+		return "minimum_not_available"
+	default:
+		return "unknown"
+	}
+}
+
+// RevisionReason records why a revision of a delegation state was written
+type RevisionReason string
+
+const (
+	RevisionReasonAutomatic RevisionReason = "automatic"
+	RevisionReasonForced    RevisionReason = "forced"
+	RevisionReasonApi       RevisionReason = "api"
+	RevisionReasonImport    RevisionReason = "import"
+)
+
 type DelegationStateBalances common.DelegatedBalances
 
 func (j DelegationStateBalances) Value() (driver.Value, error) {
@@ -34,6 +57,24 @@ func (j *DelegationStateBalances) Scan(src interface{}) error {
 	return json.Unmarshal(source, j)
 }
 
+type StakingParameters common.StakingParameters
+
+func (j StakingParameters) Value() (driver.Value, error) {
+	result, err := json.Marshal(j)
+	return string(result), err
+}
+
+func (j *StakingParameters) Scan(src interface{}) error {
+	if srcTmp, ok := src.(string); ok {
+		src = []byte(srcTmp)
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return errors.New("type assertion .([]byte) failed")
+	}
+	return json.Unmarshal(source, j)
+}
+
 type Address struct {
 	mavryk.Address
 }
@@ -79,6 +120,14 @@ type StoredDelegationState struct {
 	Cycle    int64                   `json:"cycle" gorm:"primaryKey"`
 	Status   DelegationStateStatus   `json:"status"`
 	Balances DelegationStateBalances `json:"balances" gorm:"type:jsonb;default:'{}'"`
+	// staking parameters of the baker at the end of the cycle, missing for states stored before they were kept
+	StakingParameters *StakingParameters `json:"staking_parameters,omitempty" gorm:"type:jsonb"`
+	// incremented on every write of the same delegate and cycle
+	Revision int64          `json:"revision"`
+	Reason   RevisionReason `json:"reason"`
+	StoredAt time.Time      `json:"stored_at"`
+	// set for states read from a cycle archive, they can no longer change
+	Archived bool `json:"-" gorm:"-"`
 }
 
 func (s *StoredDelegationState) OwnDelegatedbalance() common.DelegatorBalances {
@@ -110,6 +159,9 @@ func (s *StoredDelegationState) ToMvktState() *MvktLikeDelegationState {
 		}
 	}
 
+	// stable order so the projection encodes and signs deterministically
+	sort.Slice(delegators, func(i, j int) bool { return delegators[i].Address.String() < delegators[j].Address.String() })
+
 	ownBalances := s.OwnDelegatedbalance()
 	externalBalances := s.ExternalDelegatedBalance()
 
@@ -126,10 +178,15 @@ func (s *StoredDelegationState) ToMvktState() *MvktLikeDelegationState {
 }
 
 func CreateStoredDelegationStateFromDelegationState(state *common.DelegationState) *StoredDelegationState {
-	return &StoredDelegationState{
+	result := &StoredDelegationState{
 		Delegate: Address{state.Baker},
 		Cycle:    state.Cycle,
 		Status:   DelegationStateStatusOk,
 		Balances: DelegationStateBalances(state.GetDelegatorAndBakerBalances()),
 	}
+	if state.Parameters != nil {
+		parameters := StakingParameters(*state.Parameters)
+		result.StakingParameters = &parameters
+	}
+	return result
 }
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/samber/lo"
+)
+
+// DelegationStateKey identifies a stored delegation state, it is the pagination cursor of queries
+type DelegationStateKey struct {
+	Cycle    int64
+	Delegate mavryk.Address
+}
+
+// Encode returns the key as an opaque cursor
+func (k *DelegationStateKey) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", k.Cycle, k.Delegate.String())))
+}
+
+func ParseDelegationStateKey(cursor string) (*DelegationStateKey, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.Join(constants.ErrInvalidCursor, err)
+	}
+	cycle, delegate, ok := strings.Cut(string(data), ":")
+	if !ok {
+		return nil, constants.ErrInvalidCursor
+	}
+	result := &DelegationStateKey{}
+	if result.Cycle, err = strconv.ParseInt(cycle, 10, 64); err != nil {
+		return nil, errors.Join(constants.ErrInvalidCursor, err)
+	}
+	if result.Delegate, err = mavryk.ParseAddress(delegate); err != nil {
+		return nil, errors.Join(constants.ErrInvalidCursor, err)
+	}
+	return result, nil
+}
+
+type DelegationStateQuery struct {
+	Delegates []mavryk.Address
+	FromCycle int64
+	ToCycle   int64
+	// limits the range to the cycles if set
+	Cycles []int64
+	// only states ordered after the key are returned
+	After        *DelegationStateKey
+	Limit        int
+	OmitBalances bool
+}
+
+// QueryDelegationStates returns up to limit stored states of the delegates in the cycle range ordered by cycle and delegate
+func (s *Store) QueryDelegationStates(query *DelegationStateQuery) ([]StoredDelegationState, error) {
+	db := s.db.Model(&StoredDelegationState{}).
+		Where("cycle >= ? AND cycle <= ?", query.FromCycle, query.ToCycle).
+		Where("delegate IN ?", lo.Map(query.Delegates, func(addr mavryk.Address, _ int) string { return addr.String() }))
+	if len(query.Cycles) > 0 {
+		db = db.Where("cycle IN ?", query.Cycles)
+	}
+	if query.After != nil {
+		db = db.Where("(cycle, delegate) > (?, ?)", query.After.Cycle, query.After.Delegate.String())
+	}
+	if query.OmitBalances {
+		db = db.Omit("balances")
+	}
+
+	var result []StoredDelegationState
+	err := db.Order("cycle, delegate").Limit(query.Limit).Find(&result).Error
+	return result, err
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"gorm.io/gorm"
+)
+
+// CycleBakerAggregate keeps the statistics of a baker for cycles pruned from detail
+type CycleBakerAggregate struct {
+	Cycle             int64   `json:"cycle" gorm:"primaryKey"`
+	Baker             Address `json:"baker" gorm:"primaryKey"`
+	OwnStaked         int64   `json:"own_staked"`
+	OwnDelegated      int64   `json:"own_delegated"`
+	ExternalStaked    int64   `json:"external_staked"`
+	ExternalDelegated int64   `json:"external_delegated"`
+	// part of external staked balance counted as delegated
+	ExternalOverstaked int64 `json:"external_overstaked"`
+	Delegators         int64 `json:"delegators"`
+}
+
+// BakingPower counts the overstaked balance as delegated like the detailed cycles
+func (a *CycleBakerAggregate) BakingPower() int64 {
+	return bakerBakingPower(a.Cycle, a.OwnStaked, a.OwnDelegated, a.ExternalStaked, a.ExternalDelegated, a.ExternalOverstaked)
+}
+
+// GetDetailedCyclesBefore returns cycles older than cycle which still have delegation states stored
+func (s *Store) GetDetailedCyclesBefore(cycle int64) ([]int64, error) {
+	var cycles []int64
+	err := s.db.Model(&StoredDelegationState{}).Distinct("cycle").Where("cycle < ?", cycle).Order("cycle").Pluck("cycle", &cycles).Error
+	return cycles, err
+}
+
+// IsCycleDetailed checks whether delegation states of the cycle are stored
+func (s *Store) IsCycleDetailed(cycle int64) (bool, error) {
+	var count int64
+	err := s.db.Model(&StoredDelegationState{}).Where("cycle = ?", cycle).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// PruneCycle deletes delegation states of the cycle with their balances and history.
+// Per baker aggregates are stored first if keepAggregates is set.
+func (s *Store) PruneCycle(cycle int64, keepAggregates bool) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if keepAggregates {
+			err := tx.Exec(`INSERT INTO cycle_baker_aggregates (cycle, baker, own_staked, own_delegated, external_staked, external_delegated, external_overstaked, delegators) ?
+				ON CONFLICT (cycle, baker) DO NOTHING`, bakerAggregates(tx, cycle)).Error
+			if err != nil {
+				return err
+			}
+		}
+		// the commitment stays, it was published and can still be checked against archived states
+		if err := tx.Where("cycle = ?", cycle).Delete(&DelegationStateHistory{}).Error; err != nil {
+			return err
+		}
+		// normalized balances are deleted by the foreign key cascade
+		return tx.Where("cycle = ?", cycle).Delete(&StoredDelegationState{}).Error
+	})
+	if err == nil {
+		s.notifyChanged(cycle, nil)
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/samber/lo"
+)
+
+// DelegateCycleRecord is the stake composition of a baker in a single cycle
+type DelegateCycleRecord struct {
+	Cycle             int64 `json:"cycle"`
+	OwnDelegated      int64 `json:"own_delegated"`
+	OwnStaked         int64 `json:"own_staked"`
+	ExternalDelegated int64 `json:"external_delegated"`
+	ExternalStaked    int64 `json:"external_staked"`
+	// part of external staked balance counted as delegated
+	ExternalOverstaked int64 `json:"external_overstaked"`
+	Delegators         int64 `json:"delegators"`
+	BakingPower        int64 `json:"baking_power"`
+	// empty for cycles served from the stored aggregates
+	Status string `json:"status,omitempty"`
+}
+
+type delegateCycleRow struct {
+	Cycle              int64
+	Status             DelegationStateStatus
+	OwnDelegated       int64
+	OwnStaked          int64
+	ExternalDelegated  int64
+	ExternalStaked     int64
+	ExternalOverstaked int64
+	Delegators         int64
+}
+
+// GetDelegateHistory returns a record per stored cycle of the baker in range [fromCycle, toCycle] ordered by cycle.
+// Cycles pruned by the retention policy are served from the stored aggregates.
+func (s *Store) GetDelegateHistory(baker mavryk.Address, fromCycle, toCycle int64) ([]DelegateCycleRecord, error) {
+	var rows []delegateCycleRow
+	err := s.db.Table("stored_delegation_states AS s").
+		Select(`s.cycle, s.status,
+			COALESCE(SUM(CASE WHEN b.delegator = b.baker THEN b.delegated_balance END), 0) AS own_delegated,
+			COALESCE(SUM(CASE WHEN b.delegator = b.baker THEN b.staked_balance END), 0) AS own_staked,
+			COALESCE(SUM(CASE WHEN b.delegator <> b.baker THEN b.delegated_balance END), 0) AS external_delegated,
+			COALESCE(SUM(CASE WHEN b.delegator <> b.baker THEN b.staked_balance END), 0) AS external_staked,
+			COALESCE(SUM(CASE WHEN b.delegator <> b.baker THEN b.overstaked_balance END), 0) AS external_overstaked,
+			COUNT(b.delegator) FILTER (WHERE b.delegator <> b.baker AND b.delegator <> ?) AS delegators`, mavryk.BurnAddress.String()).
+		Joins("LEFT JOIN delegation_state_balances AS b ON b.baker = s.delegate AND b.cycle = s.cycle").
+		Where("s.delegate = ? AND s.cycle >= ? AND s.cycle <= ?", baker.String(), fromCycle, toCycle).
+		Group("s.cycle, s.status").
+		Order("s.cycle").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]DelegateCycleRecord, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, DelegateCycleRecord{
+			Cycle:              row.Cycle,
+			OwnDelegated:       row.OwnDelegated,
+			OwnStaked:          row.OwnStaked,
+			ExternalDelegated:  row.ExternalDelegated,
+			ExternalStaked:     row.ExternalStaked,
+			ExternalOverstaked: row.ExternalOverstaked,
+			Delegators:         row.Delegators,
+			BakingPower:        bakerBakingPower(row.Cycle, row.OwnStaked, row.OwnDelegated, row.ExternalStaked, row.ExternalDelegated, row.ExternalOverstaked),
+			Status:             row.Status.String(),
+		})
+	}
+
+	detailed := lo.Map(rows, func(row delegateCycleRow, _ int) int64 { return row.Cycle })
+	query := s.db.Where("baker = ? AND cycle >= ? AND cycle <= ?", baker.String(), fromCycle, toCycle)
+	if len(detailed) > 0 {
+		query = query.Where("cycle NOT IN ?", detailed)
+	}
+	var aggregates []CycleBakerAggregate
+	if err := query.Find(&aggregates).Error; err != nil {
+		return nil, err
+	}
+	if len(aggregates) == 0 {
+		return result, nil
+	}
+	for _, aggregate := range aggregates {
+		result = append(result, DelegateCycleRecord{
+			Cycle:              aggregate.Cycle,
+			OwnDelegated:       aggregate.OwnDelegated,
+			OwnStaked:          aggregate.OwnStaked,
+			ExternalDelegated:  aggregate.ExternalDelegated,
+			ExternalStaked:     aggregate.ExternalStaked,
+			ExternalOverstaked: aggregate.ExternalOverstaked,
+			Delegators:         aggregate.Delegators,
+			BakingPower:        aggregate.BakingPower(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Cycle < result[j].Cycle })
+	return result, nil
+}
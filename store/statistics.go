@@ -0,0 +1,189 @@
+package store
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/mavryk-network/mvgo/mavryk"
+	"github.com/mavryk-network/protocol-rewards/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CycleStatisticsPayload common.CycleStatistics
+
+func (j CycleStatisticsPayload) Value() (driver.Value, error) {
+	result, err := json.Marshal(j)
+	return string(result), err
+}
+
+func (j *CycleStatisticsPayload) Scan(src interface{}) error {
+	if srcTmp, ok := src.(string); ok {
+		src = []byte(srcTmp)
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return errors.New("type assertion .([]byte) failed")
+	}
+	return json.Unmarshal(source, j)
+}
+
+// StoredCycleStatistics caches the statistics of a completed cycle, it outlives pruning of the cycle
+type StoredCycleStatistics struct {
+	Cycle      int64                  `json:"cycle" gorm:"primaryKey"`
+	Statistics CycleStatisticsPayload `json:"statistics" gorm:"type:jsonb"`
+	ComputedAt time.Time              `json:"computed_at"`
+}
+
+func (StoredCycleStatistics) TableName() string {
+	return "cycle_statistics"
+}
+
+// cycleStatisticsRow is a baker of the cycle, or the whole network if Network is set
+type cycleStatisticsRow struct {
+	Network            bool
+	Baker              string
+	Status             DelegationStateStatus
+	OwnStaked          int64
+	OwnDelegated       int64
+	ExternalStaked     int64
+	ExternalDelegated  int64
+	ExternalOverstaked int64
+	// overstaked balance counts as delegated
+	Staked     int64
+	Delegated  int64
+	Delegators int
+	Stakers    int
+	SizeMin    int64
+	SizeMedian int64
+	SizeMax    int64
+}
+
+// cycleStatistics aggregates the normalized balances per baker and over the whole network in a single query.
+// Delegators and stakers of the network are counted once, the burn address is not counted as delegator.
+func cycleStatistics(db *gorm.DB, cycle int64) ([]cycleStatisticsRow, error) {
+	delegator := "b.delegator <> b.baker AND b.delegator <> @burn"
+	size := "b.delegated_balance + b.staked_balance"
+	var rows []cycleStatisticsRow
+	err := db.Raw(`SELECT GROUPING(s.delegate) = 1 AS network, COALESCE(s.delegate, '') AS baker, COALESCE(s.status, 0) AS status,
+			COALESCE(SUM(b.staked_balance) FILTER (WHERE b.delegator = b.baker), 0) AS own_staked,
+			COALESCE(SUM(b.delegated_balance) FILTER (WHERE b.delegator = b.baker), 0) AS own_delegated,
+			COALESCE(SUM(b.staked_balance) FILTER (WHERE b.delegator <> b.baker), 0) AS external_staked,
+			COALESCE(SUM(b.delegated_balance) FILTER (WHERE b.delegator <> b.baker), 0) AS external_delegated,
+			COALESCE(SUM(b.overstaked_balance) FILTER (WHERE b.delegator <> b.baker), 0) AS external_overstaked,
+			COALESCE(SUM(b.staked_balance - b.overstaked_balance), 0) AS staked,
+			COALESCE(SUM(b.delegated_balance + b.overstaked_balance), 0) AS delegated,
+			COUNT(DISTINCT b.delegator) FILTER (WHERE `+delegator+`) AS delegators,
+			COUNT(DISTINCT b.delegator) FILTER (WHERE `+delegator+` AND b.staked_balance > 0) AS stakers,
+			COALESCE(MIN(`+size+`) FILTER (WHERE `+delegator+`), 0) AS size_min,
+			COALESCE(FLOOR(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY `+size+`) FILTER (WHERE `+delegator+`)), 0)::bigint AS size_median,
+			COALESCE(MAX(`+size+`) FILTER (WHERE `+delegator+`), 0) AS size_max
+		FROM stored_delegation_states AS s
+		LEFT JOIN delegation_state_balances AS b ON b.baker = s.delegate AND b.cycle = s.cycle
+		WHERE s.cycle = @cycle
+		GROUP BY GROUPING SETS ((s.delegate, s.status), ())`,
+		sql.Named("burn", mavryk.BurnAddress.String()), sql.Named("cycle", cycle)).Scan(&rows).Error
+	return rows, err
+}
+
+// ComputeStatistics computes the statistics of the cycle from the stored balances.
+// Cycles pruned by the retention policy are computed from the stored aggregates.
+func (s *Store) ComputeStatistics(cycle int64) (*common.CycleStatistics, error) {
+	result := common.NewCycleStatistics(cycle)
+	rows, err := cycleStatistics(s.db, cycle)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if row.Network {
+			result.Network.Delegators = row.Delegators
+			result.Network.Stakers = row.Stakers
+			result.Network.Staked = row.Staked
+			result.Network.Delegated = row.Delegated
+			result.Network.Overstaked = row.ExternalOverstaked
+			result.Network.DelegatorSizes = common.DelegatorSizeStatistics{Min: row.SizeMin, Median: row.SizeMedian, Max: row.SizeMax}
+			continue
+		}
+		baker, err := mavryk.ParseAddress(row.Baker)
+		if err != nil {
+			return nil, err
+		}
+		result.Add(baker, common.DelegateCycleStatistics{
+			OwnStaked:          row.OwnStaked,
+			OwnDelegated:       row.OwnDelegated,
+			ExternalStaked:     row.ExternalStaked,
+			ExternalDelegated:  row.ExternalDelegated,
+			ExternalOverstaked: row.ExternalOverstaked,
+			Delegators:         row.Delegators,
+			Stakers:            row.Stakers,
+			BakingPower:        common.BakingPower(cycle, row.Staked, row.Delegated),
+			DelegatorSizes:     common.DelegatorSizeStatistics{Min: row.SizeMin, Median: row.SizeMedian, Max: row.SizeMax},
+			Status:             row.Status.String(),
+		})
+	}
+
+	if len(result.Delegates) == 0 {
+		var aggregates []CycleBakerAggregate
+		if err := s.db.Where("cycle = ?", cycle).Find(&aggregates).Error; err != nil {
+			return nil, err
+		}
+		for _, aggregate := range aggregates {
+			result.Add(aggregate.Baker.Address, common.DelegateCycleStatistics{
+				OwnStaked:          aggregate.OwnStaked,
+				OwnDelegated:       aggregate.OwnDelegated,
+				ExternalStaked:     aggregate.ExternalStaked,
+				ExternalDelegated:  aggregate.ExternalDelegated,
+				ExternalOverstaked: aggregate.ExternalOverstaked,
+				Delegators:         int(aggregate.Delegators),
+				BakingPower:        aggregate.BakingPower(),
+			})
+			// delegators of several bakers are counted for each of them
+			result.Network.Delegators += int(aggregate.Delegators)
+			result.Network.Staked += aggregate.OwnStaked + aggregate.ExternalStaked - aggregate.ExternalOverstaked
+			result.Network.Delegated += aggregate.OwnDelegated + aggregate.ExternalDelegated + aggregate.ExternalOverstaked
+			result.Network.Overstaked += aggregate.ExternalOverstaked
+		}
+	}
+	result.ComputeNetworkShares()
+	return result, nil
+}
+
+// CacheStatistics computes the statistics of the cycle and stores them in the summary table
+func (s *Store) CacheStatistics(cycle int64) (*common.CycleStatistics, error) {
+	// taken before reading so states stored meanwhile mark the result outdated
+	computedAt := time.Now().UTC()
+	statistics, err := s.ComputeStatistics(cycle)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&StoredCycleStatistics{
+		Cycle:      cycle,
+		Statistics: CycleStatisticsPayload(*statistics),
+		ComputedAt: computedAt,
+	}).Error
+	return statistics, err
+}
+
+// Statistics returns the cached statistics of the cycle unless a delegation state was stored
+// after they were computed, otherwise they are computed on the fly
+func (s *Store) Statistics(cycle int64) (*common.CycleStatistics, error) {
+	var cached StoredCycleStatistics
+	result := s.db.Where("cycle = ?", cycle).Limit(1).Find(&cached)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected > 0 {
+		outdated, err := s.HasDelegationStatesStoredAfter(cycle, cached.ComputedAt)
+		if err != nil {
+			return nil, err
+		}
+		if !outdated {
+			statistics := common.CycleStatistics(cached.Statistics)
+			return &statistics, nil
+		}
+	}
+	return s.ComputeStatistics(cycle)
+}
@@ -1,25 +1,42 @@
 package store
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/mavryk-network/mvgo/mavryk"
-	"github.com/mavryk-network/protocol-rewards/common"
 	"github.com/mavryk-network/protocol-rewards/configuration"
 	"github.com/mavryk-network/protocol-rewards/constants"
+	"github.com/samber/lo"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
 type Store struct {
-	db     *gorm.DB
-	config configuration.StorageConfiguration
+	db      *gorm.DB
+	changes changeListeners
 }
 
+// NewStore connects to the database and applies pending migrations.
+// Fails if the database schema is newer than the binary.
 func NewStore(config *configuration.Runtime) (*Store, error) {
+	result, err := OpenStore(config)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := result.MigrateUp(0); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// OpenStore connects to the database without touching the schema
+func OpenStore(config *configuration.Runtime) (*Store, error) {
 	host, port, user, pass, database := config.Database.Unwrap()
 	slog.Debug("connecting to database", "host", host, "port", port, "user", user, "database", database)
 
@@ -38,10 +55,8 @@ func NewStore(config *configuration.Runtime) (*Store, error) {
 	if err != nil {
 		return nil, err
 	}
-	db.AutoMigrate(&StoredDelegationState{})
 	return &Store{
-		db:     db,
-		config: config.Storage,
+		db: db,
 	}, nil
 }
 
@@ -56,30 +71,42 @@ func (s *Store) GetDelegationState(delegate mavryk.Address, cycle int64) (*Store
 	return &state, nil
 }
 
+// StoreDelegationState inserts or replaces the delegation state, replaced revisions are kept in the history
 func (s *Store) StoreDelegationState(state *StoredDelegationState) error {
-	// update if exists
-	if result := s.db.Model(&StoredDelegationState{}).Where("delegate = ? AND cycle = ?", state.Delegate, state.Cycle).Updates(state); result.RowsAffected > 0 && result.Error == nil {
-		return nil
-	}
-
 	slog.Debug("storing delegation state", "delegate", state.Delegate.String(), "cycle", state.Cycle)
-	if err := s.db.Create(state).Error; err != nil {
-		return err
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		return upsertDelegationState(tx, state)
+	})
+	if err == nil {
+		s.notifyChanged(state.Cycle, &state.Delegate.Address)
 	}
-	return nil
+	return err
 }
 
-func (s *Store) PruneDelegationState(cycle int64) error {
-	if s.config.Mode != constants.Rolling {
-		return nil
+// ForEachDelegationState iterates over stored delegation states of cycles in range [fromCycle, toCycle]
+// ordered by cycle and delegate. Optionally limited to the delegates passed.
+func (s *Store) ForEachDelegationState(fromCycle, toCycle int64, delegates []mavryk.Address, f func(state *StoredDelegationState) error) error {
+	query := s.db.Model(&StoredDelegationState{}).Where("cycle >= ? AND cycle <= ?", fromCycle, toCycle)
+	if len(delegates) > 0 {
+		query = query.Where("delegate IN ?", lo.Map(delegates, func(addr mavryk.Address, _ int) string { return addr.String() }))
 	}
 
-	prunedCycle := cycle - int64(s.config.StoredCycles)
-
-	state := &StoredDelegationState{}
-	slog.Debug("pruning delegation states smaller than", "cycle", prunedCycle)
-	return s.db.Model(&StoredDelegationState{}).Where("cycle < ?", prunedCycle).Delete(state).Error
+	rows, err := query.Order("cycle, delegate").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
 
+	for rows.Next() {
+		var state StoredDelegationState
+		if err := s.db.ScanRows(rows, &state); err != nil {
+			return err
+		}
+		if err := f(&state); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
 
 func (s *Store) IsDelegationStateAvailable(delegate mavryk.Address, cycle int64) (bool, error) {
@@ -88,53 +115,76 @@ func (s *Store) IsDelegationStateAvailable(delegate mavryk.Address, cycle int64)
 	return count > 0, nil
 }
 
-func (s *Store) Statistics(cycle int64) (*common.CycleStatistics, error) {
-	var states []StoredDelegationState
-	if err := s.db.Model(&StoredDelegationState{}).Where("cycle = ?", cycle).Find(&states).Error; err != nil {
-		return nil, err
+func (s *Store) GetLastFetchedCycle() (int64, error) {
+	var cycle int64
+
+	if err := s.db.Model(&StoredDelegationState{}).Select("cycle").Order("cycle desc").First(&cycle).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, nil
+		}
+		return 0, err
 	}
+	return cycle, nil
+}
 
-	result := &common.CycleStatistics{
-		Cycle:     cycle,
-		Delegates: make(map[mavryk.Address]common.DelegateCycleStatistics),
+// MergeDelegationStates inserts the states in a single transaction. Existing states are replaced
+// as a new revision only if overwrite is set. Returns the number of inserted or replaced states.
+func (s *Store) MergeDelegationStates(states []*StoredDelegationState, overwrite bool) (int64, error) {
+	if len(states) == 0 {
+		return 0, nil
 	}
 
-	for _, state := range states {
-		ownStaked := int64(0)
-		ownDelegated := int64(0)
-		externalStaked := int64(0)
-		externalDelegated := int64(0)
-
-		for addr, balances := range state.Balances {
-			switch {
-			case addr.Equal(state.Delegate.Address):
-				ownDelegated += balances.DelegatedBalance
-				ownStaked += balances.StakedBalance
-			default:
-				externalStaked += balances.StakedBalance
-				externalDelegated += balances.DelegatedBalance
+	var affected int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, state := range states {
+			state.Reason = RevisionReasonImport
+			if overwrite {
+				if err := upsertDelegationState(tx, state); err != nil {
+					return err
+				}
+				affected++
+				continue
 			}
-		}
 
-		result.Delegates[state.Delegate.Address] = common.DelegateCycleStatistics{
-			OwnStaked:         ownStaked,
-			OwnDelegated:      ownDelegated,
-			ExternalStaked:    externalStaked,
-			ExternalDelegated: externalDelegated,
+			if err := lockDelegationState(tx, state); err != nil {
+				return err
+			}
+			state.Revision = 1
+			state.StoredAt = time.Now().UTC()
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(state)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			if err := replaceBalances(tx, state); err != nil {
+				return err
+			}
+			affected++
+		}
+		return nil
+	})
+	if err == nil && affected > 0 {
+		for _, state := range states {
+			s.notifyChanged(state.Cycle, &state.Delegate.Address)
 		}
 	}
-
-	return result, nil
+	return affected, err
 }
 
-func (s *Store) GetLastFetchedCycle() (int64, error) {
-	var cycle int64
+// HasDelegationStatesStoredAfter checks whether a delegation state of the cycle was stored after t
+func (s *Store) HasDelegationStatesStoredAfter(cycle int64, t time.Time) (bool, error) {
+	var count int64
+	err := s.db.Model(&StoredDelegationState{}).Where("cycle = ? AND stored_at > ?", cycle, t).Limit(1).Count(&count).Error
+	return count > 0, err
+}
 
-	if err := s.db.Model(&StoredDelegationState{}).Select("cycle").Order("cycle desc").First(&cycle).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return 0, nil
-		}
-		return 0, err
+// Ping checks the database connection
+func (s *Store) Ping(ctx context.Context) error {
+	db, err := s.db.DB()
+	if err != nil {
+		return err
 	}
-	return cycle, nil
+	return db.PingContext(ctx)
 }
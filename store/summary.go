@@ -0,0 +1,72 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mavryk-network/protocol-rewards/constants"
+	"gorm.io/gorm"
+)
+
+// CycleSummary holds values computed over all delegation states of a cycle.
+// Every changed root is kept as a new version so published roots stay verifiable.
+type CycleSummary struct {
+	Cycle      int64     `json:"cycle" gorm:"primaryKey"`
+	Version    int64     `json:"version" gorm:"primaryKey"`
+	MerkleRoot string    `json:"merkle_root"`
+	Leaves     int64     `json:"leaves"`
+	Bakers     int64     `json:"bakers"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+// lockCycleSummary serializes commits of the cycle until the transaction ends
+func lockCycleSummary(tx *gorm.DB, cycle int64) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext('cycle_summaries'), ?)", cycle).Error
+}
+
+// StoreCycleSummary stores the summary as the next version of the cycle. If the root did not change
+// the latest version is kept with the newer computation time and copied into summary, states stored
+// in between are covered by the root.
+func (s *Store) StoreCycleSummary(summary *CycleSummary) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockCycleSummary(tx, summary.Cycle); err != nil {
+			return err
+		}
+		var latest CycleSummary
+		result := tx.Where("cycle = ?", summary.Cycle).Order("version DESC").Limit(1).Find(&latest)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 && latest.MerkleRoot == summary.MerkleRoot {
+			if summary.ComputedAt.After(latest.ComputedAt) {
+				latest.ComputedAt = summary.ComputedAt
+				err := tx.Model(&CycleSummary{}).Where("cycle = ? AND version = ?", latest.Cycle, latest.Version).
+					Update("computed_at", latest.ComputedAt).Error
+				if err != nil {
+					return err
+				}
+			}
+			*summary = latest
+			return nil
+		}
+		summary.Version = latest.Version + 1
+		return tx.Create(summary).Error
+	})
+}
+
+// GetCycleSummary returns the version of the cycle summary, 0 means the latest version
+func (s *Store) GetCycleSummary(cycle, version int64) (*CycleSummary, error) {
+	query := s.db.Where("cycle = ?", cycle)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	}
+	var summary CycleSummary
+	if err := query.Order("version DESC").First(&summary).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Join(constants.ErrNotFound, fmt.Errorf("cycle %d summary", cycle))
+		}
+		return nil, err
+	}
+	return &summary, nil
+}